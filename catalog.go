@@ -0,0 +1,253 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// A Catalog provides translations of the messages written by the library, and of
+// Command and Option descriptions, for a locale.
+//
+// Messages are fmt format strings, identified by the Msg* keys, and translations
+// must take the same arguments in the same order as the defaults.  Descriptions are
+// identified by the DescKey set on each Command or Option.
+type Catalog interface {
+	// Message returns the translation of key for locale, or false if there is none.
+	Message(locale string, key string) (string, bool)
+}
+
+// MapCatalog is a Catalog held in memory, mapping each locale to a map of keys to
+// their translations, e.g.
+//   clicommand.MapCatalog{
+//     "de": {clicommand.MsgHelpSubcommands: "Verfügbare Unterbefehle:"},
+//   }
+type MapCatalog map[string]map[string]string
+
+// Message returns the translation of key for locale, or false if there is none.
+func (m MapCatalog) Message(locale string, key string) (string, bool) {
+	msg, ok := m[locale][key]
+	return msg, ok
+}
+
+// Message keys used by the library, with the default message for each.
+const (
+	MsgHelpAliases         = "help.aliases"           // Aliases:
+	MsgHelpOptions         = "help.options"           // %s options:
+	MsgHelpOptionRules     = "help.optionrules"       // Option rules:
+	MsgHelpSubcommands     = "help.subcommands"       // Available subcommands:
+	MsgHelpTopics          = "help.topics"            // Help topics:
+	MsgHelpExamples        = "help.examples"          // Examples:
+	MsgHelpInformation     = "help.information"       // For help information run:
+	MsgHelpRequired        = "help.required"          // Required:
+	MsgHelpDeprecated      = "help.deprecated"        // Deprecated:
+	MsgHelpDeprecatedUse   = "help.deprecated.use"    // Deprecated, use %s:
+	MsgHelpUsage           = "help.usage"             // Usage:
+	MsgHelpError           = "help.error"             // Error: %s
+	MsgHelpRun             = "help.run"               // For help information, run: %s help
+	MsgSearchCommands      = "search.commands"        // Commands matching '%s':
+	MsgSearchTopics        = "search.topics"          // Help topics matching '%s':
+	MsgSearchNone          = "search.none"            // No commands matching '%s'
+	MsgSearchKeyword       = "search.keyword"         // help -k requires a keyword
+	MsgTreeLeaf            = "tree.leaf"              // leaf
+	MsgTreeParent          = "tree.parent"            // parent
+	MsgTreeHidden          = "tree.hidden"            // hidden
+	MsgTreeDeprecated      = "tree.deprecated"        // deprecated
+	MsgRuleExclusive       = "rule.exclusive"         // mutually exclusive: %s
+	MsgRuleRequires        = "rule.requires"          // %s requires: %s
+	MsgRuleAtLeastOne      = "rule.atleastone"        // at least one of: %s
+	MsgRuleExactlyOne      = "rule.exactlyone"        // exactly one of: %s
+	MsgWarnCommand         = "warn.command"           // Warning: command '%s' %s
+	MsgWarnOption          = "warn.option"            // Warning: option '%s' %s
+	MsgDeprecated          = "deprecated"             // is deprecated
+	MsgDeprecatedMessage   = "deprecated.message"     // is deprecated: %s
+	MsgDeprecatedUse       = "deprecated.use"         // , use '%s' instead
+	MsgErrCallback         = "error.callback"         // Callback error: %s
+	MsgErrCallbackPre      = "error.callbackpre"      // CallbackPre error: %s
+	MsgErrCancelled        = "error.cancelled"        // Cancelled: %s
+	MsgErrCancelledSignal  = "error.cancelled.signal" // Cancelled: received signal %s
+	MsgErrCommandError     = "error.command"          // Error: %s
+	MsgErrCommandInvalid   = "error.command.invalid"  // Invalid subcommand: %s
+	MsgErrCommandMissing   = "error.command.missing"  // No subcommand specified
+	MsgErrExampleInvalid   = "error.example"          // Invalid example for %s: %s: %s
+	MsgErrExitCode         = "error.exitcode"         // Exit code %d
+	MsgErrMountConflict    = "error.mount.conflict"   // Cannot mount '%s' from %s beneath '%s': conflicts with '%s' from %s
+	MsgErrMountPoint       = "error.mount.point"      // Mount point not found: %s
+	MsgErrOptionConflict   = "error.option.conflict"  // Conflicting options: %s
+	MsgErrOptionGroup      = "error.option.group"     // One of options required: %s
+	MsgErrOptionMissing    = "error.option.missing"   // Required option missing: %s
+	MsgErrOptionMissingArg = "error.option.param"     // Missing parameter to option: %s
+	MsgErrOptionRequires   = "error.option.requires"  // Option %s requires option: %s
+	MsgErrOptionUnknown    = "error.option.unknown"   // Unknown option: %s
+	MsgErrTreeInvalid      = "error.tree"             // Invalid command tree: %s
+)
+
+// defaultMessages are the messages used when the Catalog has no translation.
+var defaultMessages = map[string]string{
+	MsgHelpAliases:         "Aliases:",
+	MsgHelpOptions:         "%s options:",
+	MsgHelpOptionRules:     "Option rules:",
+	MsgHelpSubcommands:     "Available subcommands:",
+	MsgHelpTopics:          "Help topics:",
+	MsgHelpExamples:        "Examples:",
+	MsgHelpInformation:     "For help information run:",
+	MsgHelpRequired:        "Required: ",
+	MsgHelpDeprecated:      "Deprecated: ",
+	MsgHelpDeprecatedUse:   "Deprecated, use %s: ",
+	MsgHelpUsage:           "Usage:",
+	MsgHelpError:           "Error: %s",
+	MsgHelpRun:             "For help information, run: %s help",
+	MsgSearchCommands:      "Commands matching '%s':",
+	MsgSearchTopics:        "Help topics matching '%s':",
+	MsgSearchNone:          "No commands matching '%s'",
+	MsgSearchKeyword:       "help -k requires a keyword",
+	MsgTreeLeaf:            "leaf",
+	MsgTreeParent:          "parent",
+	MsgTreeHidden:          "hidden",
+	MsgTreeDeprecated:      "deprecated",
+	MsgRuleExclusive:       "mutually exclusive: %s",
+	MsgRuleRequires:        "%s requires: %s",
+	MsgRuleAtLeastOne:      "at least one of: %s",
+	MsgRuleExactlyOne:      "exactly one of: %s",
+	MsgWarnCommand:         "Warning: command '%s' %s",
+	MsgWarnOption:          "Warning: option '%s' %s",
+	MsgDeprecated:          "is deprecated",
+	MsgDeprecatedMessage:   "is deprecated: %s",
+	MsgDeprecatedUse:       ", use '%s' instead",
+	MsgErrCallback:         "Callback error: %s",
+	MsgErrCallbackPre:      "CallbackPre error: %s",
+	MsgErrCancelled:        "Cancelled: %s",
+	MsgErrCancelledSignal:  "Cancelled: received signal %s",
+	MsgErrCommandError:     "Error: %s",
+	MsgErrCommandInvalid:   "Invalid subcommand: %s",
+	MsgErrCommandMissing:   "No subcommand specified",
+	MsgErrExampleInvalid:   "Invalid example for %s: %s: %s",
+	MsgErrExitCode:         "Exit code %d",
+	MsgErrMountConflict:    "Cannot mount '%s' from %s beneath '%s': conflicts with '%s' from %s",
+	MsgErrMountPoint:       "Mount point not found: %s",
+	MsgErrOptionConflict:   "Conflicting options: %s",
+	MsgErrOptionGroup:      "One of options required: %s",
+	MsgErrOptionMissing:    "Required option missing: %s",
+	MsgErrOptionMissingArg: "Missing parameter to option: %s",
+	MsgErrOptionRequires:   "Option %s requires option: %s",
+	MsgErrOptionUnknown:    "Unknown option: %s",
+	MsgErrTreeInvalid:      "Invalid command tree: %s",
+}
+
+// SetCatalog sets the Catalog used to translate messages and descriptions for the
+// whole tree.  This should only be called on the root Command.
+func (c *Command) SetCatalog(catalog Catalog) *Command {
+	c.Catalog = catalog
+	return c
+}
+
+// SetLocale sets the locale used to translate messages for the whole tree, in
+// place of detecting it from the environment.  This should only be called on the
+// root Command.
+func (c *Command) SetLocale(locale string) *Command {
+	c.Locale = locale
+	return c
+}
+
+// GetLocale returns the locale used to translate messages.  This is the Locale set
+// on the root Command if any, otherwise the first of the LC_ALL, LC_MESSAGES and
+// LANG environment variables which is set.
+func (c *Command) GetLocale() string {
+	if root := c.GetRoot(); root.Locale != "" {
+		return root.Locale
+	}
+
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			return locale
+		}
+	}
+
+	return ""
+}
+
+// translate looks up key within the Catalog of the tree for the current locale.
+// The locale is tried in full, then without any codeset or modifier, then as just
+// the language, e.g. "de_DE.UTF-8", "de_DE", "de".
+func (c *Command) translate(key string) (string, bool) {
+	root := c.GetRoot()
+	if root.Catalog == nil || key == "" {
+		return "", false
+	}
+
+	locale := c.GetLocale()
+	candidates := []string{locale}
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+		candidates = append(candidates, locale)
+	}
+	if i := strings.IndexAny(locale, "_-"); i >= 0 {
+		candidates = append(candidates, locale[:i])
+	}
+
+	for _, candidate := range candidates {
+		if candidate == "" || candidate == "C" || candidate == "POSIX" {
+			continue
+		}
+
+		if msg, ok := root.Catalog.Message(candidate, key); ok {
+			return msg, true
+		}
+	}
+
+	return "", false
+}
+
+// message formats the message key, translated for the tree cmd is within.  cmd
+// may be nil, in which case the default message is used.
+func message(cmd *Command, key string, args ...interface{}) string {
+	format := defaultMessages[key]
+	if cmd != nil {
+		if msg, ok := cmd.translate(key); ok {
+			format = msg
+		}
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// SetDescKey sets the key used to look up a translation of the Command description
+// within the Catalog of the tree.
+func (c *Command) SetDescKey(key string) *Command {
+	c.DescKey = key
+	return c
+}
+
+// GetDesc returns the description of the Command, translated via the Catalog of the
+// tree if it has a DescKey with a translation.
+func (c *Command) GetDesc() string {
+	if msg, ok := c.translate(c.DescKey); ok {
+		return msg
+	}
+
+	return c.Desc
+}
+
+// SetDescKey sets the key used to look up a translation of the Option description
+// within the Catalog of the tree.
+func (o *Option) SetDescKey(key string) *Option {
+	o.DescKey = key
+	return o
+}
+
+// GetDesc returns the description of the Option, translated via the Catalog of the
+// tree cmd is within if it has a DescKey with a translation.
+func (o *Option) GetDesc(cmd *Command) string {
+	if msg, ok := cmd.translate(o.DescKey); ok {
+		return msg
+	}
+
+	return o.Desc
+}
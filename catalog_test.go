@@ -0,0 +1,136 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCatalog German translations of a few messages and descriptions
+var testCatalog = MapCatalog{
+	"de": {
+		MsgHelpOptions:      "%s Optionen:",
+		MsgHelpRequired:     "Erforderlich: ",
+		MsgErrOptionUnknown: "Unbekannte Option: %s",
+		MsgHelpError:        "Fehler: %s",
+		MsgHelpRun:          "Hilfe erhalten Sie mit: %s help",
+		"child.desc":        "Kind Beschreibung",
+		"param.desc":        "Parameter Beschreibung",
+	},
+}
+
+// newCatalogTree creates the help tree with the test catalog and descriptions keys
+func newCatalogTree(locale string) (*Command, *Command) {
+	cmdRoot, _ := newHelpTree()
+	cmdRoot.SetCatalog(testCatalog).SetLocale(locale)
+
+	cmdChild := cmdRoot.GetCommand(cmdChildName).SetDescKey("child.desc")
+	cmdChild.GetOption("param", true).SetDescKey("param.desc")
+	cmdChild.SetOptionRequired(cmdChild.GetOption("param", true))
+
+	return cmdRoot, cmdChild
+}
+
+// GetLocale testing, validate the root Locale takes precedence over the environment
+func TestGetLocale(t *testing.T) {
+	cmdRoot, cmdChild := newCatalogTree("")
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "fr_FR.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "fr_FR.UTF-8", cmdChild.GetLocale())
+
+	cmdRoot.SetLocale("de")
+	assert.Equal(t, "de", cmdChild.GetLocale())
+}
+
+// message testing, validate locale fallback and the English defaults
+func TestMessage(t *testing.T) {
+	cmdRoot, cmdChild := newCatalogTree("de_AT.UTF-8@euro")
+
+	assert.Equal(t, "Unbekannte Option: -x", message(cmdChild, MsgErrOptionUnknown, "-x"))
+	assert.Equal(t, "Invalid subcommand: x", message(cmdChild, MsgErrCommandInvalid, "x"))
+	assert.Equal(t, "Unknown option: -x", message(nil, MsgErrOptionUnknown, "-x"))
+	assert.Equal(t, "Kind Beschreibung", cmdChild.GetDesc())
+
+	cmdRoot.SetLocale("C")
+	assert.Equal(t, "Unknown option: -x", message(cmdChild, MsgErrOptionUnknown, "-x"))
+	assert.Equal(t, cmdChildDesc, cmdChild.GetDesc())
+}
+
+// defaultMessages testing, validate every Msg key declared within catalog.go has a
+// default message
+func TestDefaultMessages(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "catalog.go", nil, 0)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var keys int
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Msg") || i >= len(spec.Values) {
+				continue
+			}
+
+			lit, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+
+			key, _ := strconv.Unquote(lit.Value)
+			assert.NotEmpty(t, defaultMessages[key], name.Name)
+			keys++
+		}
+
+		return true
+	})
+
+	assert.Len(t, defaultMessages, keys)
+}
+
+// Catalog testing, validate help and errors are translated
+func TestCatalogHelp(t *testing.T) {
+	cmdRoot, _ := newCatalogTree("de")
+	out := cmdRoot.Stdout.(interface{ String() string })
+
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "help"})
+	assert.Equal(t, `
+child - Kind Beschreibung
+root [-option] child --param <param>
+Aliases: alias
+
+root Optionen:
+   -option option description
+
+root child Optionen:
+  --param <arg> Erforderlich: Parameter Beschreibung
+
+`, out.String())
+
+	cmdRoot.ErrorVerbosity = ErrorsUsage
+	buf := cmdRoot.Stdout.(interface{ Reset() })
+	buf.Reset()
+
+	err := cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "-x"})
+	assert.EqualError(t, err, "Unbekannte Option: -x")
+	assert.Equal(t, `Usage: root [-option] child --param <param>
+Fehler: Unbekannte Option: -x
+
+Hilfe erhalten Sie mit: root child help
+`, out.String())
+}
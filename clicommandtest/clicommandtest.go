@@ -0,0 +1,147 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+// Package clicommandtest provides helpers for testing programs built with
+// clicommand, without swapping os.Args or capturing the real standard output.
+//
+// Run executes a command line under a tree, capturing everything written via the
+// writers of the root Command, e.g.
+//   res := clicommandtest.Run(cliRoot, "say", "-u", "hello")
+//   if res.ExitCode != 0 || res.Stdout != "HELLO\n" {
+//     t.Errorf("unexpected result: %+v", res)
+//   }
+//
+// Handlers must write their output via Data.Stdout() and Data.Stderr() for it to
+// be captured.
+//
+// Resolve and the Assert functions instead check which Command and Options a
+// command line selects, without running validation callbacks or handlers, e.g.
+//   data, err := clicommandtest.Resolve(cliRoot, "say", "-u", "hello")
+//   if err != nil {
+//     t.Fatal(err)
+//   }
+//   clicommandtest.AssertHandler(t, data, sayHandler)
+//   clicommandtest.AssertOptions(t, data, map[string]string{"u": ""})
+package clicommandtest
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leehuk/go-clicommand"
+	"github.com/leehuk/go-clicommand/internal/hook"
+)
+
+// HelpWidth is the width help is wrapped to by Run, unless HelpWidth is already set
+// on the root Command, so output does not depend on the environment.
+const HelpWidth = 80
+
+// Result is the outcome of running a command line via Run.
+type Result struct {
+	// Stdout Everything written to the standard output writer of the root Command
+	Stdout string
+	// Stderr Everything written to the error writer of the root Command
+	Stderr string
+	// Err Error returned by ParseContext
+	Err error
+	// ExitCode Exit code Command.Run would return
+	ExitCode int
+	// Data Data passed to the callbacks and Handler, or holding the position reached
+	// within the tree if parsing failed
+	Data *clicommand.Data
+}
+
+// Run executes args under the tree exactly as Command.Run does, with args excluding
+// the program name.  Output is captured by temporarily replacing the Stdout and
+// Stderr writers of the root Command, so Run must not be called concurrently on
+// the same tree.
+func Run(root *clicommand.Command, args ...string) *Result {
+	return RunContext(context.Background(), root, args...)
+}
+
+// RunContext is the same as Run, but executes args under the supplied context.
+func RunContext(ctx context.Context, root *clicommand.Command, args ...string) *Result {
+	var stdout, stderr bytes.Buffer
+	var result = &Result{}
+
+	root = root.GetRoot()
+	argv := append([]string{root.Name}, args...)
+
+	savedStdout, savedStderr, savedWidth := root.Stdout, root.Stderr, root.HelpWidth
+	defer func() {
+		root.Stdout, root.Stderr, root.HelpWidth = savedStdout, savedStderr, savedWidth
+	}()
+
+	root.Stdout = &stdout
+	root.Stderr = &stderr
+	if root.HelpWidth == 0 {
+		root.HelpWidth = HelpWidth
+	}
+
+	data, err := hook.ParseContext(ctx, root, argv)
+	result.Data = data.(*clicommand.Data)
+	result.Err = err
+	result.ExitCode = root.ReportError(result.Err)
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	return result
+}
+
+// Resolve resolves args under the tree via Command.Resolve, with args excluding the
+// program name.  Pre-validation callbacks are run, as they may supply options, but
+// no validation callbacks or handlers are.
+func Resolve(root *clicommand.Command, args ...string) (*clicommand.Data, error) {
+	root = root.GetRoot()
+	return root.Resolve(append([]string{root.Name}, args...))
+}
+
+// AssertCommand checks the Command selected within data is at path beneath the
+// root, reporting an error via t if not.  An empty path selects the root.
+func AssertCommand(t testing.TB, data *clicommand.Data, path ...string) bool {
+	t.Helper()
+
+	want := strings.Join(append([]string{data.Cmd.GetNameTop()}, path...), " ")
+	if got := data.Cmd.GetNameChain(); got != want {
+		t.Errorf("selected command %q, want %q", got, want)
+		return false
+	}
+
+	return true
+}
+
+// AssertHandler checks the Handler of the Command selected within data is handler,
+// reporting an error via t if not.  Handlers are compared by their code, so
+// closures created from the same function literal are treated as equal.
+func AssertHandler(t testing.TB, data *clicommand.Data, handler clicommand.Handler) bool {
+	t.Helper()
+
+	if data.IsHelp() {
+		t.Errorf("help selected for %q, want a handler", data.Cmd.GetNameChain())
+		return false
+	}
+
+	if data.Cmd.Handler == nil || reflect.ValueOf(data.Cmd.Handler).Pointer() != reflect.ValueOf(handler).Pointer() {
+		t.Errorf("selected command %q does not have the wanted handler", data.Cmd.GetNameChain())
+		return false
+	}
+
+	return true
+}
+
+// AssertOptions checks the options supplied within data are exactly options,
+// reporting an error via t if not.  Options without a parameter have an empty value.
+func AssertOptions(t testing.TB, data *clicommand.Data, options map[string]string) bool {
+	t.Helper()
+
+	if len(data.Options) != len(options) || len(options) > 0 && !reflect.DeepEqual(data.Options, options) {
+		t.Errorf("selected options %v, want %v", data.Options, options)
+		return false
+	}
+
+	return true
+}
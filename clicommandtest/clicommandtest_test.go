@@ -0,0 +1,124 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommandtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/leehuk/go-clicommand"
+	"github.com/stretchr/testify/assert"
+)
+
+var sayCalled bool
+
+func sayHandler(data *clicommand.Data) error {
+	sayCalled = true
+	if _, ok := data.Options["fail"]; ok {
+		return errors.New("failed")
+	}
+
+	fmt.Fprintf(data.Stdout(), "%v\n", data.Params)
+	return nil
+}
+
+// mockTB records failures rather than failing the test
+type mockTB struct {
+	testing.TB
+	failed   bool
+	messages []string
+}
+
+func (m *mockTB) Helper() {}
+
+func (m *mockTB) Errorf(format string, args ...interface{}) {
+	m.failed = true
+	m.messages = append(m.messages, fmt.Sprintf(format, args...))
+}
+
+// newTestTree creates a tree with a single leaf, "root say"
+func newTestTree() *clicommand.Command {
+	cmdRoot := clicommand.NewCommand("root", "root description", nil)
+	cmdRoot.NewOption("v", "verbose", false)
+	cmdSay := cmdRoot.NewCommand("say", "say description", sayHandler)
+	cmdSay.NewOption("fail", "fail", false)
+	cmdSay.NewOption("to", "recipient", true)
+
+	return cmdRoot
+}
+
+// Run testing, validate output, errors and exit codes are captured
+func TestRun(t *testing.T) {
+	cmdRoot := newTestTree()
+
+	res := Run(cmdRoot, "say", "hello")
+	assert.Equal(t, "[hello]\n", res.Stdout)
+	assert.Equal(t, "", res.Stderr)
+	assert.Nil(t, res.Err)
+	assert.Equal(t, clicommand.ExitSuccess, res.ExitCode)
+	AssertCommand(t, res.Data, "say")
+
+	res = Run(cmdRoot, "say", "-fail")
+	assert.Equal(t, "Error: failed\n", res.Stderr)
+	assert.IsType(t, &clicommand.ErrCommandError{}, res.Err)
+	assert.Equal(t, clicommand.ExitFailure, res.ExitCode)
+
+	res = Run(cmdRoot, "bogus")
+	assert.Contains(t, res.Stderr, "Error: Invalid subcommand: bogus\n")
+	assert.Equal(t, clicommand.ExitUsage, res.ExitCode)
+	AssertCommand(t, res.Data)
+
+	assert.Nil(t, cmdRoot.Stdout)
+	assert.Nil(t, cmdRoot.Stderr)
+	assert.Equal(t, 0, cmdRoot.HelpWidth)
+}
+
+// Run testing, validate Data is that passed to the Handler
+func TestRunData(t *testing.T) {
+	type ctxKey struct{}
+	var handlerData *clicommand.Data
+
+	cmdRoot := newTestTree()
+	cmdRoot.BindCallbackPre(func(data *clicommand.Data) error {
+		data.Options["to"] = "everyone"
+		return nil
+	})
+	cmdRoot.NewCommand("capture", "capture description", func(data *clicommand.Data) error {
+		handlerData = data
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	res := RunContext(ctx, cmdRoot, "capture")
+	assert.Nil(t, res.Err)
+	assert.Same(t, handlerData, res.Data)
+	assert.Equal(t, "value", res.Data.Ctx.Value(ctxKey{}))
+	assert.Equal(t, "everyone", res.Data.Options["to"])
+}
+
+// Resolve testing, validate the selection is checked without running the handler
+func TestResolve(t *testing.T) {
+	cmdRoot := newTestTree()
+	sayCalled = false
+
+	data, err := Resolve(cmdRoot, "-v", "say", "--to", "world", "hello")
+	assert.Nil(t, err)
+	assert.True(t, AssertCommand(t, data, "say"))
+	assert.True(t, AssertHandler(t, data, sayHandler))
+	assert.True(t, AssertOptions(t, data, map[string]string{"v": "", "to": "world"}))
+	assert.Equal(t, []string{"hello"}, data.Params)
+	assert.False(t, sayCalled)
+
+	mock := &mockTB{}
+	assert.False(t, AssertCommand(mock, data))
+	assert.False(t, AssertOptions(mock, data, nil))
+	assert.True(t, mock.failed)
+
+	data, err = Resolve(cmdRoot, "say", "help")
+	assert.Nil(t, err)
+	assert.True(t, data.IsHelp())
+	assert.False(t, AssertHandler(mock, data, sayHandler))
+}
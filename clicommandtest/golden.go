@@ -0,0 +1,133 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommandtest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leehuk/go-clicommand"
+)
+
+// update is set via the -clicommandtest.update flag to rewrite golden files.
+var update = flag.Bool("clicommandtest.update", false, "rewrite clicommandtest golden files")
+
+// AssertHelpGolden renders help for every Command within the tree, including hidden
+// commands, and compares each with its golden file within dir, reporting an error
+// via t showing the command path and a line diff for each that differs, e.g.
+//   func TestHelp(t *testing.T) {
+//     clicommandtest.AssertHelpGolden(t, cliRoot, "testdata/help")
+//   }
+//
+// Golden files are named after the command path, e.g. "root_api_get.golden".  When
+// the test is run with the -clicommandtest.update flag, the golden files are
+// rewritten instead.  Help is wrapped to HelpWidth unless HelpWidth is already set
+// on the root Command.
+func AssertHelpGolden(t testing.TB, root *clicommand.Command, dir string) bool {
+	t.Helper()
+
+	root = root.GetRoot()
+	savedWidth := root.HelpWidth
+	defer func() {
+		root.HelpWidth = savedWidth
+	}()
+
+	if root.HelpWidth == 0 {
+		root.HelpWidth = HelpWidth
+	}
+
+	if *update {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Errorf("creating golden directory: %s", err)
+			return false
+		}
+	}
+
+	var ok = true
+	root.Walk(func(cmd *clicommand.Command, depth int) error {
+		if !goldenCompare(t, cmd, dir) {
+			ok = false
+		}
+		return nil
+	})
+
+	return ok
+}
+
+// goldenCompare compares help for cmd with its golden file, or rewrites it if
+// updating.
+func goldenCompare(t testing.TB, cmd *clicommand.Command, dir string) bool {
+	t.Helper()
+
+	var got bytes.Buffer
+	chain := cmd.GetNameChain()
+	path := filepath.Join(dir, strings.ReplaceAll(chain, " ", "_")+".golden")
+
+	if err := cmd.WriteHelp(&got); err != nil {
+		t.Errorf("help for %q: %s", chain, err)
+		return false
+	}
+
+	if *update {
+		if err := os.WriteFile(path, got.Bytes(), 0644); err != nil {
+			t.Errorf("help for %q: %s", chain, err)
+			return false
+		}
+	} else if want, err := os.ReadFile(path); err != nil {
+		t.Errorf("help for %q: %s, run with -clicommandtest.update to create it", chain, err)
+		return false
+	} else if !bytes.Equal(want, got.Bytes()) {
+		t.Errorf("help for %q differs from %s (-want +got):\n%s", chain, path, lineDiff(string(want), got.String()))
+		return false
+	}
+
+	return true
+}
+
+// lineDiff returns a line based diff between want and got, prefixing lines only in
+// want with "-", lines only in got with "+" and common lines with a space.
+func lineDiff(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j >= len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			diff.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+
+	return diff.String()
+}
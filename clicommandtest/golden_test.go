@@ -0,0 +1,44 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommandtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertHelpGolden testing, validate help matches the checked in golden files
+func TestAssertHelpGolden(t *testing.T) {
+	AssertHelpGolden(t, newTestTree(), "testdata/help")
+}
+
+// AssertHelpGolden testing, validate changed and missing golden files are reported
+func TestAssertHelpGoldenDiff(t *testing.T) {
+	dir := t.TempDir()
+	cmdRoot := newTestTree()
+
+	*update = true
+	assert.True(t, AssertHelpGolden(t, cmdRoot, dir))
+	*update = false
+
+	cmdRoot.GetCommand("say").Desc = "changed description"
+	assert.Nil(t, os.Remove(filepath.Join(dir, "root.golden")))
+
+	mock := &mockTB{}
+	assert.False(t, AssertHelpGolden(mock, cmdRoot, dir))
+	if assert.Len(t, mock.messages, 2) {
+		assert.Contains(t, mock.messages[0], `help for "root": open `)
+		assert.Contains(t, mock.messages[1], `help for "root say" differs`)
+		assert.Contains(t, mock.messages[1], "\n- say - say description\n+ say - changed description\n")
+	}
+}
+
+// lineDiff testing, validate changed lines are marked
+func TestLineDiff(t *testing.T) {
+	assert.Equal(t, "  a\n- b\n+ c\n  d\n", lineDiff("a\nb\nd", "a\nc\nd"))
+	assert.Equal(t, "  a\n+ b\n", lineDiff("a", "a\nb"))
+}
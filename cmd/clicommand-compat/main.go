@@ -0,0 +1,78 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package main
+
+/*
+clicommand-compat compares the specs of two releases of a command tree, as written by
+Command.WriteSpec(), reporting breaking changes, additions and deprecations:
+
+	./clicommand-compat old.json new.json
+
+It exits 0 if the new release is compatible, 1 if there are breaking changes, or 2 if
+the specs could not be read, so can be used directly within CI.
+*/
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/leehuk/go-clicommand"
+)
+
+func readSpec(path string) (*clicommand.Spec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	spec, err := clicommand.ReadSpec(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return spec, nil
+}
+
+func compat(data *clicommand.Data) error {
+	if len(data.Params) != 2 {
+		return clicommand.NewErrExitCode(clicommand.ExitUsage, fmt.Errorf("expected two specs, run: %s help", data.Cmd.GetNameChain()))
+	}
+
+	var specs []*clicommand.Spec
+	for _, path := range data.Params {
+		spec, err := readSpec(path)
+		if err != nil {
+			return clicommand.NewErrExitCode(clicommand.ExitUsage, err)
+		}
+		specs = append(specs, spec)
+	}
+
+	var breaking int
+	_, quiet := data.Options["quiet"]
+	for _, change := range clicommand.DiffSpec(specs[0], specs[1]) {
+		if change.Kind == clicommand.ChangeBreaking {
+			breaking++
+		} else if quiet {
+			continue
+		}
+
+		fmt.Fprintf(data.Stdout(), "%s\n", change)
+	}
+
+	if breaking > 0 {
+		fmt.Fprintf(data.Stderr(), "%d breaking changes\n", breaking)
+		return clicommand.NewErrExitCode(clicommand.ExitFailure, nil)
+	}
+
+	return nil
+}
+
+func main() {
+	cliRoot := clicommand.NewCommand("clicommand-compat", "Compare two command tree specs for breaking changes", compat)
+	cliRoot.SetLongDesc("Takes the specs of the old and new release, as written by Command.WriteSpec(), as parameters.")
+	cliRoot.NewOption("quiet", "Only report breaking changes", false)
+
+	os.Exit(cliRoot.Run(os.Args))
+}
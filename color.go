@@ -0,0 +1,106 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"io"
+	"os"
+)
+
+// A Palette defines the ANSI escape sequences used to colour help and error
+// output.  Any field left empty is not styled.
+type Palette struct {
+	// Heading Section headings within help
+	Heading string
+	// Option Option names within help
+	Option string
+	// Required Required markers within help
+	Required string
+	// Error The "Error:" line written when parsing fails
+	Error string
+}
+
+// DefaultPalette is the Palette used when none is set on the root Command.
+var DefaultPalette = &Palette{
+	Heading:  "\x1b[1m",
+	Option:   "\x1b[36m",
+	Required: "\x1b[33m",
+	Error:    "\x1b[1;31m",
+}
+
+// colorReset is the ANSI escape sequence ending any styling.
+const colorReset = "\x1b[0m"
+
+// style wraps text in the escape sequence code, leaving it unstyled if code is
+// empty.
+func style(code string, text string) string {
+	if code == "" || text == "" {
+		return text
+	}
+
+	return code + text + colorReset
+}
+
+// heading styles a section heading.  A nil Palette leaves text unstyled.
+func (p *Palette) heading(text string) string {
+	if p == nil {
+		return text
+	}
+
+	return style(p.Heading, text)
+}
+
+// option styles an option name.  A nil Palette leaves text unstyled.
+func (p *Palette) option(text string) string {
+	if p == nil {
+		return text
+	}
+
+	return style(p.Option, text)
+}
+
+// required styles a required marker.  A nil Palette leaves text unstyled.
+func (p *Palette) required(text string) string {
+	if p == nil {
+		return text
+	}
+
+	return style(p.Required, text)
+}
+
+// error styles an error line.  A nil Palette leaves text unstyled.
+func (p *Palette) error(text string) string {
+	if p == nil {
+		return text
+	}
+
+	return style(p.Error, text)
+}
+
+// SetPalette sets the Palette used to colour help and error output for the whole
+// tree.  This should only be called on the root Command.
+func (c *Command) SetPalette(palette *Palette) *Command {
+	c.Palette = palette
+	return c
+}
+
+// getPalette returns the Palette to colour output written to out, or nil if
+// output should not be coloured.  Colour is only used when out is a terminal and
+// is disabled by NoColor, the NO_COLOR environment variable, or the built in
+// -no-color option.  This should only be called on the root Command.
+func (c *Command) getPalette(out io.Writer, data *Data) *Palette {
+	if c.NoColor || data.noColor || os.Getenv("NO_COLOR") != "" {
+		return nil
+	}
+
+	if _, ok := terminalWidth(out); !ok {
+		return nil
+	}
+
+	if c.Palette != nil {
+		return c.Palette
+	}
+
+	return DefaultPalette
+}
@@ -5,7 +5,9 @@ package clicommand
 
 import (
 	"fmt"
-	"strings"
+	"io"
+	"os"
+	"reflect"
 )
 
 // A Command represents a command of the cli program.  These are chained into a tree
@@ -22,6 +24,20 @@ type Command struct {
 	Name string
 	// Desc Description of subcommand
 	Desc string
+	// DescKey Key of the translation of Desc within the Catalog, or empty
+	DescKey string
+	// LongDesc Long-form description of subcommand, shown within its own help
+	LongDesc string
+	// Examples Sample invocations of subcommand, shown within its own help
+	Examples []*Example
+	// Footer Text shown at the end of help for subcommand
+	Footer string
+	// Topics Help topics registered on the tree.  Only used on the root Command.
+	Topics []*Topic
+	// Aliases Alternative names the subcommand can be selected by
+	Aliases []string
+	// Group Name of the group subcommand is listed under by its parent, or empty
+	Group string
 	// Handler Handler function subcommand calls, nil for subcommands with children
 	Handler Handler
 	// Parent Command object thats the parent of this one
@@ -30,10 +46,63 @@ type Command struct {
 	Children []*Command
 	// Options Option arguments
 	Options []*Option
+	// OptionGroups Relationships between Option arguments
+	OptionGroups []*OptionGroup
+	// RequiredOptions Option arguments required for this command and its children
+	RequiredOptions []*Option
+	// Hidden Omit this command from help output, whilst still parsing it
+	Hidden bool
+	// Deprecated Deprecation details, or nil if the command is not deprecated
+	Deprecated *Deprecation
+	// HelpTemplate text/template used to render help for this command and its
+	// children, empty to inherit from the parent or use DefaultHelpTemplate
+	HelpTemplate string
 	// Callbackspre Callbacks to run pre-verification
 	Callbackspre []Handler
 	// Callbacks Callbacks to run as part of verification
 	Callbacks []Handler
+	// SignalCancel Cancel the parsing context on SIGINT or SIGTERM.  Only used on
+	// the root Command.
+	SignalCancel bool
+	// Stdout Writer for help output, nil for os.Stdout.  Only used on the root Command.
+	Stdout io.Writer
+	// Stderr Writer for errors, nil for os.Stderr.  Only used on the root Command.
+	Stderr io.Writer
+	// HelpWidth Width help is wrapped to, 0 to detect the terminal width.  Only used
+	// on the root Command.
+	HelpWidth int
+	// HelpTree Enable "help -tree", showing an overview of the tree.  Only used on
+	// the root Command.
+	HelpTree bool
+	// CommandOrder Order children are listed in.  Only used on the root Command.
+	CommandOrder CommandOrder
+	// Palette Colours for help and errors, nil for DefaultPalette.  Only used on the
+	// root Command.
+	Palette *Palette
+	// NoColor Disable colour in help and errors.  Only used on the root Command.
+	NoColor bool
+	// ErrorVerbosity Information written alongside parsing errors.  Only used on
+	// the root Command.
+	ErrorVerbosity ErrorVerbosity
+	// Catalog Translations of messages and descriptions, nil for English.  Only
+	// used on the root Command.
+	Catalog Catalog
+	// Locale Locale messages are translated for, empty to detect it from the
+	// environment.  Only used on the root Command.
+	Locale string
+	// Strict Refuse to parse the command line if the tree fails Validate().  Only
+	// used on the root Command.
+	Strict bool
+	// MultiCall Select the Command parsing starts from via the program name.  Only
+	// used on the root Command.
+	MultiCall bool
+
+	// errorVerbosity Per error type overrides of ErrorVerbosity
+	errorVerbosity map[reflect.Type]ErrorVerbosity
+	// source Package which mounted the Command, or empty
+	source string
+	// lookup Index of children and visible options, or nil if not indexed
+	lookup *lookupIndex
 }
 
 // NewCommand creates a new command, unbound to parents.  This is generally only used
@@ -46,6 +115,7 @@ func NewCommand(name string, desc string, handler Handler) *Command {
 		Name:    name,
 		Desc:    desc,
 		Handler: handler,
+		lookup:  &lookupIndex{},
 	}
 
 	return cmd
@@ -61,8 +131,28 @@ func (c *Command) NewCommand(name string, desc string, handler Handler) *Command
 	return cmd
 }
 
+// SetHidden marks the Command as hidden, so it is omitted from help output but is
+// otherwise parsed as normal.  This is useful for internal or debugging commands.
+func (c *Command) SetHidden() *Command {
+	c.Hidden = true
+	return c
+}
+
+// SetDeprecated marks the Command as deprecated.  It continues to be parsed as
+// normal, but a warning is written to the error writer whenever it is used.
+// message and replacement are both optional, with replacement naming the command
+// the user should use instead.
+func (c *Command) SetDeprecated(message string, replacement string) *Command {
+	c.Deprecated = &Deprecation{
+		Message:     message,
+		Replacement: replacement,
+	}
+	return c
+}
+
 // BindCommand binds a series of subcommands as children.  Links are placed in both
-// directions, from parent -> child and child -> parent.
+// directions, from parent -> child and child -> parent.  Subcommands already bound to
+// another parent are first unbound from it.
 //
 // If the parent already has a handler set, this will panic.
 func (c *Command) BindCommand(cmdv ...*Command) {
@@ -70,22 +160,86 @@ func (c *Command) BindCommand(cmdv ...*Command) {
 		panic(fmt.Sprintf("BindCommand() Parent has handler function set: %s", c.GetNameChain()))
 	}
 
+	for _, cmd := range cmdv {
+		if cmd.Parent != nil {
+			cmd.Parent.UnbindCommand(cmd)
+		}
+	}
+
 	c.Children = append(c.Children, cmdv...)
 	for _, cmd := range cmdv {
 		cmd.Parent = c
 	}
+
+	c.invalidateLookup()
 }
 
-// GetCommand finds a child Command with the given name, or nil if not found.
-// name matches are case-insensitive.
+// GetCommand finds a child Command with the given name or alias, or nil if not
+// found.  name matches are case-insensitive.
+//
+// Lookups use an index which is rebuilt whenever the tree is changed via its
+// methods, so do not depend on the number of children.  Each hit is checked against
+// the fields of the tree, and a miss falls back to scanning the children, so lookups
+// remain correct if Children, Name or Aliases are changed directly.
 func (c *Command) GetCommand(name string) *Command {
-	for _, cmd := range c.Children {
-		if strings.EqualFold(cmd.Name, name) {
-			return cmd
-		}
+	if cmd, ok := c.lookupCommand(name); ok {
+		return cmd
 	}
 
-	return nil
+	cmd := c.scanCommand(name)
+	if cmd != nil {
+		// the index missed a change made directly to the tree
+		c.resetLookup()
+	}
+
+	return cmd
+}
+
+// SetLongDesc sets the long-form description of the Command, shown beneath the
+// short description within its own help output.  Blank lines separate paragraphs.
+func (c *Command) SetLongDesc(longdesc string) *Command {
+	c.LongDesc = longdesc
+	return c
+}
+
+// SetFooter sets text shown at the end of the Command help output.
+func (c *Command) SetFooter(footer string) *Command {
+	c.Footer = footer
+	return c
+}
+
+// SetName renames the Command, rebuilding the lookup index on next use.
+func (c *Command) SetName(name string) *Command {
+	c.Name = name
+	c.invalidateLookup()
+	return c
+}
+
+// SetAliases adds alternative names the Command can be selected by on the command
+// line.  Aliases are only matched when no child has the name itself.
+func (c *Command) SetAliases(aliasv ...string) *Command {
+	c.Aliases = append(c.Aliases, aliasv...)
+	c.invalidateLookup()
+	return c
+}
+
+// SetHelpTemplate sets the text/template used to render help for this Command and
+// its children.  Setting this on the root Command customises help for the whole
+// tree.  The template is executed with HelpData, and has the additional functions:
+//   join        strings.Join
+//   msg         translates a message key from the Catalog, with arguments
+//   rule        describes an OptionGroup
+//   deprecated  prefix for a deprecated Command or Option, from its Deprecation
+//   required    marker for a required Option, or empty, styled via the Palette
+//   heading     styles a heading via the Palette
+//   option      styles an option name via the Palette
+//   wrap        wraps text to the help width, indented by the given columns
+//   row         wraps a label and text into columns, the label padded to the
+//               given width
+// See DefaultHelpTemplate.
+func (c *Command) SetHelpTemplate(text string) *Command {
+	c.HelpTemplate = text
+	return c
 }
 
 // NewOption creates a new Option and automatically binds it as a child.
@@ -111,29 +265,70 @@ func (c *Command) UnbindOption(optionv ...*Option) {
 
 // GetOption finds an child Option with the given name and the same parameter,
 // searching the entire way up the tree to the root if necessary.
+//
+// As with GetCommand(), lookups use an index so do not depend on the number of
+// options or the depth of the tree, and remain correct if Options or Name are
+// changed directly.
 func (c *Command) GetOption(name string, param bool) *Option {
-	for _, option := range c.Options {
-		if strings.EqualFold(option.Name, name) && option.Param == param {
-			return option
-		}
+	if option, ok := c.lookupOption(name, param); ok {
+		return option
 	}
 
-	// not found, may be a parameter to a parent menu
-	if c.Parent != nil {
-		return c.Parent.GetOption(name, param)
+	option := c.scanOption(name, param)
+	if option != nil {
+		// the index missed a change made directly to the tree
+		c.resetLookup()
 	}
 
-	return nil
+	return option
+}
+
+// SetOptionRequired marks Options so they must be specified whenever this Command
+// or any of its children is selected.  Unlike Option.SetRequired(), the requirement
+// is specific to this point in the tree, so an Option bound to a parent can be
+// required for one child but remain optional for its siblings, e.g.
+//   optForce := cliApi.NewOption("force", "Force operation", false)
+//   cliApiDelete.SetOptionRequired(optForce)
+//
+// The Options must be available to this Command, either bound directly or to one
+// of its parents.
+func (c *Command) SetOptionRequired(optionv ...*Option) *Command {
+	c.RequiredOptions = append(c.RequiredOptions, optionv...)
+	return c
+}
+
+// GetOptionRequired returns whether the Option must be specified when this Command
+// is selected, either as the Option is itself marked required, or it has been marked
+// required for this Command or one of its parents via SetOptionRequired().
+func (c *Command) GetOptionRequired(option *Option) bool {
+	if option.Required {
+		return true
+	}
+
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		for _, required := range cmd.RequiredOptions {
+			if required == option {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 // hasRequiredOptions iterates over all attached Option entries in the tree validating
 // any marked as being required, are appropriately set.  It starts at the leaf and
-// moves up towards the root.
-func (c *Command) hasRequiredOptions(data *Data) error {
+// moves up towards the root, returning the first missing Option or nil.
+func (c *Command) hasRequiredOptions(data *Data) *Option {
 	for _, option := range c.Options {
 		if _, ok := data.Options[option.Name]; option.Required && !ok {
-			return fmt.Errorf("Required option missing: %s", option.Name)
+			return option
+		}
+	}
 
+	for _, option := range c.RequiredOptions {
+		if _, ok := data.Options[option.Name]; !ok {
+			return option
 		}
 	}
 
@@ -146,8 +341,9 @@ func (c *Command) hasRequiredOptions(data *Data) error {
 
 // BindCallbackPre binds a pre-validation callback, that can be used to alter
 // the user-provided options and Command tree prior to validation.  This can be
-// useful for things like translating environment variables into options, or
-// making options required/not-required for certain commands.
+// useful for things like translating environment variables into options.  To make
+// options required for certain commands, use SetOptionRequired() instead as any
+// changes made to the tree here are seen by every command.
 //
 // Callbacks are processed starting at the leaf, moving up to the root. Only
 // callbacks directly on that path are executed.
@@ -211,9 +407,34 @@ func (c *Command) GetNameChain() string {
 
 // GetNameTop finds the name of the root Command.
 func (c *Command) GetNameTop() string {
+	return c.GetRoot().Name
+}
+
+// GetRoot finds the root Command of the tree.
+func (c *Command) GetRoot() *Command {
 	if c.Parent != nil {
-		return c.Parent.GetNameTop()
+		return c.Parent.GetRoot()
+	}
+
+	return c
+}
+
+// getStdout returns the writer for standard output.  This should only be called
+// on the root Command.
+func (c *Command) getStdout() io.Writer {
+	if c.Stdout != nil {
+		return c.Stdout
+	}
+
+	return os.Stdout
+}
+
+// getStderr returns the writer for errors.  This should only be called on the
+// root Command.
+func (c *Command) getStderr() io.Writer {
+	if c.Stderr != nil {
+		return c.Stderr
 	}
 
-	return c.Name
+	return os.Stderr
 }
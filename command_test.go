@@ -200,3 +200,33 @@ func TestGetNameParent(t *testing.T) {
 
 	assert.Equal(cmdRootName, cmdChild.GetNameTop(), "Command.GetNameTop()")
 }
+
+// SetOptionRequired, validate an option bound to the parent is required only
+// for the child it is set on
+func TestSetOptionRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot := newCommandRoot(nil)
+	option := cmdRoot.newOption()
+	cmdChild := cmdRoot.newCommandChild(nil).SetOptionRequired(option)
+	cmdSibling := cmdRoot.NewCommand("sibling", cmdChildDesc, nil)
+
+	assert.False(cmdRoot.GetOptionRequired(option))
+	assert.True(cmdChild.GetOptionRequired(option))
+	assert.False(cmdSibling.GetOptionRequired(option))
+
+	data := &Data{Cmd: cmdChild, Options: make(map[string]string)}
+	assert.Equal(option, cmdChild.hasRequiredOptions(data))
+	assert.Nil(cmdSibling.hasRequiredOptions(data))
+}
+
+// SetAliases, validate a child can be found by its alias
+func TestSetAliases(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot := newCommandRoot(nil)
+	cmdChild := cmdRoot.newCommandChild(nil).SetAliases("alias")
+
+	assert.Equal(cmdChild, cmdRoot.GetCommand("ALIAS"))
+	assert.Nil(cmdRoot.GetCommand("missing"))
+}
@@ -0,0 +1,366 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind categorises a Change between two Specs.
+type ChangeKind int
+
+const (
+	// ChangeBreaking A command line accepted by the old tree may now fail, or
+	// behave differently.
+	ChangeBreaking ChangeKind = iota
+	// ChangeAddition A command, name or option has been added.
+	ChangeAddition
+	// ChangeDeprecation A command or option has been deprecated.
+	ChangeDeprecation
+)
+
+// String returns the name of the kind of change.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeBreaking:
+		return "breaking"
+	case ChangeAddition:
+		return "addition"
+	case ChangeDeprecation:
+		return "deprecation"
+	}
+
+	return "unknown"
+}
+
+// A Change is a difference in the interface of a command tree, found by DiffSpec().
+type Change struct {
+	// Kind Category of the change
+	Kind ChangeKind
+	// Path Space separated names of the commands from the root, as named in the new
+	// Spec where the command still exists
+	Path string
+	// Message Description of the change
+	Message string
+}
+
+// String describes the change, prefixed by its kind and command path.
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Kind, c.Path, c.Message)
+}
+
+// DiffSpec compares the Spec of an old release of a tree, from, against a new release, to,
+// returning every Change found in the order of the tree.  The root commands are
+// always compared, even if renamed.
+//
+// Breaking changes are removed commands, names and options, options changing
+// between a flag and a parameter option, newly required options and commands
+// which now require a subcommand, along with new or tightened option groups.
+// Options or option groups moved to a parent of the command, or commands renamed
+// whilst keeping their old name as an alias, are still accepted and so are not
+// breaking.
+func DiffSpec(from, to *Spec) []Change {
+	var changes []Change
+	diffSpecRecurse(&changes, to.Name, from, to, nil, nil, nil)
+	return changes
+}
+
+// specVisible holds the options and option groups visible at a command within a
+// Spec, and which options are required there.
+type specVisible struct {
+	options  []*SpecOption
+	groups   []*SpecOptionGroup
+	required map[string]bool
+}
+
+// newSpecVisible adds the options bound to spec to those visible at its parent.
+func newSpecVisible(parent *specVisible, spec *Spec) *specVisible {
+	visible := &specVisible{required: make(map[string]bool)}
+	if parent != nil {
+		visible.options = append(visible.options, parent.options...)
+		visible.groups = append(visible.groups, parent.groups...)
+		for flag := range parent.required {
+			visible.required[flag] = true
+		}
+	}
+
+	visible.options = append(visible.options, spec.Options...)
+	visible.groups = append(visible.groups, spec.OptionGroups...)
+	for _, option := range visible.options {
+		if option.Required {
+			visible.required[specOptionFlag(option)] = true
+		}
+	}
+
+	for _, flag := range spec.RequiredOptions {
+		visible.required[strings.ToLower(flag)] = true
+	}
+
+	return visible
+}
+
+// find returns the visible option with the given name, preferring one with the
+// given param type, or nil.
+func (v *specVisible) find(name string, param bool) *SpecOption {
+	var found *SpecOption
+	for _, option := range v.options {
+		if strings.EqualFold(option.Name, name) && (found == nil || option.Param == param) {
+			found = option
+		}
+	}
+
+	return found
+}
+
+// available returns the flags, in lower case, of those options within flags which
+// are visible.
+func (v *specVisible) available(flags []string) []string {
+	var found []string
+	for _, flag := range flags {
+		name := strings.TrimLeft(flag, "-")
+		if option := v.find(name, strings.HasPrefix(flag, "--")); option != nil && specOptionFlag(option) == strings.ToLower(flag) {
+			found = append(found, strings.ToLower(flag))
+		}
+	}
+
+	return found
+}
+
+// exclusive returns whether at most one of flags may already be given, as all are
+// within a single exclusive or exactly one group.
+func (v *specVisible) exclusive(flags []string) bool {
+	if len(flags) <= 1 {
+		return true
+	}
+
+	for _, group := range v.groups {
+		if (group.Type == "exclusive" || group.Type == "exactlyone") && specFlagsSubset(flags, group.Options) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// atLeastOne returns whether at least one of flags must already be given, as a
+// group requiring one of a subset of flags exists.
+func (v *specVisible) atLeastOne(flags []string) bool {
+	for _, flag := range flags {
+		if v.required[strings.ToLower(flag)] {
+			return true
+		}
+	}
+
+	for _, group := range v.groups {
+		if (group.Type == "atleastone" || group.Type == "exactlyone") && specFlagsSubset(group.Options, flags) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requires returns whether flag must already be given alongside option.
+func (v *specVisible) requires(option string, flag string) bool {
+	if v.required[strings.ToLower(flag)] {
+		return true
+	}
+
+	for _, group := range v.groups {
+		if group.Type == "requires" && len(group.Options) > 1 && strings.EqualFold(group.Options[0], option) &&
+			specFlagsSubset([]string{flag}, group.Options[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// covers returns whether every command line using the visible options, and
+// satisfying the visible option groups, also satisfies group.
+func (v *specVisible) covers(group *SpecOptionGroup) bool {
+	switch group.Type {
+	case "exclusive":
+		return v.exclusive(v.available(group.Options))
+	case "requires":
+		if len(group.Options) < 2 || len(v.available(group.Options[:1])) == 0 {
+			return true
+		}
+		for _, flag := range group.Options[1:] {
+			if !v.requires(group.Options[0], flag) {
+				return false
+			}
+		}
+		return true
+	case "atleastone":
+		return v.atLeastOne(group.Options)
+	case "exactlyone":
+		return v.atLeastOne(group.Options) && v.exclusive(v.available(group.Options))
+	}
+
+	return true
+}
+
+// specFlagsSubset returns whether every flag within sub is within flags, ignoring
+// case.
+func specFlagsSubset(sub []string, flags []string) bool {
+	for _, flag := range sub {
+		var found bool
+		for _, candidate := range flags {
+			found = found || strings.EqualFold(flag, candidate)
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// diffSpecRecurse compares from and to, which are the same command within the old
+// and new trees, and their children.  newly holds the options which became required
+// at a parent, so each is only reported once.
+func diffSpecRecurse(changes *[]Change, path string, from, to *Spec, oldParent, newParent *specVisible, newly map[string]bool) {
+	add := func(kind ChangeKind, format string, args ...interface{}) {
+		*changes = append(*changes, Change{kind, path, fmt.Sprintf(format, args...)})
+	}
+
+	if from.Leaf && !to.Leaf {
+		add(ChangeBreaking, "now requires a subcommand")
+	} else if !from.Leaf && to.Leaf {
+		add(ChangeAddition, "now runs without a subcommand")
+	}
+
+	if from.Deprecated == nil && to.Deprecated != nil {
+		add(ChangeDeprecation, "command %s", to.Deprecated)
+	}
+
+	if oldParent != nil {
+		for _, name := range specNames(from) {
+			if !specHasName(to, name) {
+				add(ChangeBreaking, "no longer accepts the name '%s'", name)
+			}
+		}
+		for _, name := range specNames(to) {
+			if !specHasName(from, name) {
+				add(ChangeAddition, "accepts the new name '%s'", name)
+			}
+		}
+	}
+
+	oldVisible := newSpecVisible(oldParent, from)
+	newVisible := newSpecVisible(newParent, to)
+
+	for _, option := range from.Options {
+		flag := specOptionFlag(option)
+		if now := newVisible.find(option.Name, option.Param); now == nil {
+			add(ChangeBreaking, "option %s removed", flag)
+		} else if now.Param != option.Param {
+			add(ChangeBreaking, "option %s changed to %s", flag, specOptionFlag(now))
+		}
+	}
+
+	for _, option := range to.Options {
+		flag := specOptionFlag(option)
+		if was := oldVisible.find(option.Name, option.Param); was == nil {
+			add(ChangeAddition, "option %s added", flag)
+		} else if was.Param == option.Param && was.Deprecated == nil && option.Deprecated != nil {
+			add(ChangeDeprecation, "option %s %s", flag, option.Deprecated)
+		}
+	}
+
+	var required []string
+	for flag := range newVisible.required {
+		required = append(required, flag)
+	}
+	sort.Strings(required)
+
+	nowNewly := make(map[string]bool)
+	for _, flag := range required {
+		if !oldVisible.required[flag] {
+			nowNewly[flag] = true
+			if !newly[flag] {
+				add(ChangeBreaking, "option %s now required", flag)
+			}
+		}
+	}
+
+	// option groups are compared where they are bound, against all groups visible
+	// within the other tree, so each is only reported once
+	for _, group := range to.OptionGroups {
+		if !oldVisible.covers(group) {
+			add(ChangeBreaking, "option rule added: %s", group)
+		}
+	}
+
+	for _, group := range from.OptionGroups {
+		if !newVisible.covers(group) {
+			add(ChangeAddition, "option rule removed: %s", group)
+		}
+	}
+
+	for _, oldChild := range from.Children {
+		newChild := specChild(to, oldChild)
+		if newChild == nil {
+			*changes = append(*changes, Change{ChangeBreaking, path + " " + oldChild.Name, "command removed"})
+			continue
+		}
+
+		diffSpecRecurse(changes, path+" "+newChild.Name, oldChild, newChild, oldVisible, newVisible, nowNewly)
+	}
+
+	for _, newChild := range to.Children {
+		var found bool
+		for _, oldChild := range from.Children {
+			found = found || specChild(to, oldChild) == newChild
+		}
+
+		if !found {
+			*changes = append(*changes, Change{ChangeAddition, path + " " + newChild.Name, "command added"})
+		}
+	}
+}
+
+// specChild returns the child of spec accepting the name of child, or one of its
+// aliases, or nil.
+func specChild(spec *Spec, child *Spec) *Spec {
+	for _, name := range specNames(child) {
+		for _, candidate := range spec.Children {
+			if specHasName(candidate, name) {
+				return candidate
+			}
+		}
+	}
+
+	return nil
+}
+
+// specNames returns the name and aliases of spec.
+func specNames(spec *Spec) []string {
+	return append([]string{spec.Name}, spec.Aliases...)
+}
+
+// specHasName returns whether spec accepts name, ignoring case.
+func specHasName(spec *Spec, name string) bool {
+	for _, candidate := range specNames(spec) {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// specOptionFlag returns the option name as it is given on the command line, in
+// lower case.
+func specOptionFlag(option *SpecOption) string {
+	if option.Param {
+		return "--" + strings.ToLower(option.Name)
+	}
+
+	return "-" + strings.ToLower(option.Name)
+}
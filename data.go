@@ -3,10 +3,25 @@
 
 package clicommand
 
+import (
+	"context"
+	"io"
+	"strings"
+)
+
 // The Data structure is passed to all Handler functions called as a result
 // of a given Command being run.  This structure is also passed to any
 // registered callbacks during the parsing stage.
 type Data struct {
+	// Ctx is the context the command line is being parsed under.  This is
+	// context.Background() when called via Parse(), or the context supplied to
+	// ParseContext().
+	//
+	// Handlers performing long running operations should watch Ctx.Done(), as
+	// when SignalCancel is set on the root Command the context is cancelled on
+	// SIGINT or SIGTERM.
+	Ctx context.Context
+
 	// Cmd is a pointer to the Command object which has triggered the Handler
 	// to be called.
 	//
@@ -15,8 +30,9 @@ type Data struct {
 	Cmd *Command
 
 	// Options is a map of options supplied to the command.  The key is the
-	// option selected by the user, with the value being the parameter supplied
-	// to that option.
+	// name of the Option selected by the user, as defined in the tree whatever
+	// case it was given in, with the value being the parameter supplied to that
+	// option.
 	//
 	// For Option objects which do not take parameters, the value is an empty
 	// string.
@@ -38,4 +54,73 @@ type Data struct {
 	// match any more commands, the remaining non-option fields become
 	// parameters.
 	Params []string
+
+	// noColor is set when the built in -no-color option is given.
+	noColor bool
+
+	// help is set when the help command was given.
+	help bool
+
+	// invoked is the Command selected by the program name, when MultiCall is set
+	// on the root Command, otherwise nil.
+	invoked *Command
+
+	// invokedName is the program name invoked was selected by.
+	invokedName string
+}
+
+// IsHelp returns whether help was requested, either via the help command or by
+// selecting the root Command without a Handler, in which case help is displayed in
+// place of calling a Handler.
+func (d *Data) IsHelp() bool {
+	return d.help
+}
+
+// Stdout returns the writer for standard output configured on the root Command.
+// Handlers writing their output here, rather than directly to os.Stdout, can have
+// it captured under test.
+func (d *Data) Stdout() io.Writer {
+	return d.Cmd.GetRoot().getStdout()
+}
+
+// Stderr returns the writer for error output configured on the root Command.
+func (d *Data) Stderr() io.Writer {
+	return d.Cmd.GetRoot().getStderr()
+}
+
+// nameChain returns the names of all Command objects from the root to cmd, as
+// GetNameChain() does, but starting from the invoked name for commands beneath a
+// Command selected by the program name.
+func (d *Data) nameChain(cmd *Command) string {
+	var names []string
+	for c := cmd; d.invoked != nil && c != nil; c = c.Parent {
+		if c == d.invoked {
+			return strings.Join(append([]string{d.invokedName}, names...), " ")
+		}
+		names = append([]string{c.Name}, names...)
+	}
+
+	return cmd.GetNameChain()
+}
+
+// invokedBeneath returns whether a Command was selected by the program name, and it
+// is cmd or one of its children.
+func (d *Data) invokedBeneath(cmd *Command) bool {
+	for c := d.invoked; c != nil; c = c.Parent {
+		if c == cmd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nameTop returns the name of the root Command, or the invoked name if a Command
+// was selected by the program name.
+func (d *Data) nameTop() string {
+	if d.invoked != nil {
+		return d.invokedName
+	}
+
+	return d.Cmd.GetNameTop()
 }
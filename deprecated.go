@@ -0,0 +1,54 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"io"
+)
+
+// A Deprecation marks a Command or Option as deprecated.  Deprecated commands and
+// options continue to be parsed as normal, but a warning is written to the error
+// writer whenever they are used.
+type Deprecation struct {
+	// Message Explanation shown to the user, may be empty
+	Message string `json:"message,omitempty"`
+	// Replacement Command or option the user should use instead, may be empty
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// String describes the deprecation, in the form used within warnings.
+func (d *Deprecation) String() string {
+	return d.describe(nil)
+}
+
+// describe describes the deprecation, translated for the tree cmd is within.  cmd
+// may be nil.
+func (d *Deprecation) describe(cmd *Command) string {
+	str := message(cmd, MsgDeprecated)
+	if d.Message != "" {
+		str = message(cmd, MsgDeprecatedMessage, d.Message)
+	}
+
+	if d.Replacement != "" {
+		str += message(cmd, MsgDeprecatedUse, d.Replacement)
+	}
+
+	return str
+}
+
+// warnDeprecatedCommand writes a warning if the Command is deprecated.
+func warnDeprecatedCommand(out io.Writer, cmd *Command) {
+	if cmd.Deprecated != nil {
+		fmt.Fprintf(out, "%s\n", message(cmd, MsgWarnCommand, cmd.GetNameChain(), cmd.Deprecated.describe(cmd)))
+	}
+}
+
+// warnDeprecatedOption writes a warning if the Option is deprecated, translated for
+// the tree cmd is within.
+func warnDeprecatedOption(out io.Writer, cmd *Command, option *Option) {
+	if option.Deprecated != nil {
+		fmt.Fprintf(out, "%s\n", message(cmd, MsgWarnOption, optionFlag(option), option.Deprecated.describe(cmd)))
+	}
+}
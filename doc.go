@@ -35,6 +35,9 @@ tree, so child commands also have options from their parent commands.  Options
 are defined as either having or not having parameters, options with parameters use
 double dashes and options without parameters use single dashes as selectors.
 
+Relationships between options, such as options which are mutually exclusive, can be
+declared with option groups and are validated by the parser.
+
 CLI Parameters
 
 Anything the parser doesnt recognise is stored as a parameter, alloowing applications to accept
@@ -44,8 +47,12 @@ Autogenerated Help
 
 As each command and option is added to the tree with a name and description, the parser can
 automatically construct help information and display it when the program is run without
-parameters, or the 'help' command is used.  The following example uses the sample helloworld
-program from https://git.io/vNDug
+parameters, or the 'help' command is used.  Help is wrapped to the width of the terminal,
+or the COLUMNS environment variable when not writing to a terminal, and is rendered via
+text/template so its layout can be customised.  When writing to a terminal, help and
+errors are coloured unless the NO_COLOR environment variable or the -no-color option is
+given.  The following example uses the sample
+helloworld program from https://git.io/vNDug
 
        [golang@908222b2e8aa helloworld]$ ./helloworld help
 
@@ -53,15 +60,22 @@ program from https://git.io/vNDug
        helloworld [-u] [-lower]
 
        helloworld options:
-          -u                    Uppercase output
-          -lower                Lowercase output
+          -u     Uppercase output
+          -lower Lowercase output
+
+       Option rules:
+         mutually exclusive: -u, -lower
 
        Available subcommands:
-         hello        Says hello world
-         say          Says something
+         hello Says hello world
+         say   Says something
 
        For help information run:
-         'helloworld help' .. 'helloworld <commands>* help' .. 'helloworld [commands]* help [subcommand]*'
+         'helloworld help' .. 'helloworld <commands>* help' .. 'helloworld help -k <keyword>'
+
+Messages written by the library, along with command and option descriptions, can be
+translated by setting a Catalog on the root command.  The locale is taken from the LC_ALL,
+LC_MESSAGES or LANG environment variables, unless one is set via SetLocale().
 
 Sample Program
 
@@ -1,80 +1,306 @@
 package clicommand
 
 import (
-	"fmt"
+	"os"
+	"strings"
 )
 
 // ErrCallback Error type for when a callback has failed.
 type ErrCallback struct {
-	data string
+	// Cmd Command that was about to be executed
+	Cmd *Command
+	// Err Error returned by the callback
+	Err error
 }
 
 // ErrCallbackPre Error type for when a pre-validation callback has failed.
 type ErrCallbackPre struct {
-	data string
+	// Cmd Command that was about to be executed
+	Cmd *Command
+	// Err Error returned by the callback
+	Err error
+}
+
+// ErrCancelled Error type for when the parsing context has been cancelled, either
+// by the caller of ParseContext() or by a signal when SignalCancel is set.
+type ErrCancelled struct {
+	// Cmd Command that was about to be, or was being, executed
+	Cmd *Command
+	// Err Cause of the cancellation, from context.Cause()
+	Err error
+	// Signal Signal that caused the cancellation, or nil
+	Signal os.Signal
 }
 
 // ErrCommandError Error type for when a command has returned an error.
 type ErrCommandError struct {
-	data string
+	// Cmd Command whose Handler returned the error
+	Cmd *Command
+	// Err Error returned by the Handler
+	Err error
 }
 
 // ErrCommandInvalid Error type for when the command line uses a subcommand
 // that does not exist.
 type ErrCommandInvalid struct {
-	data string
+	// Cmd Parent Command that was searched for the subcommand
+	Cmd *Command
+	// Name Subcommand given on the command line
+	Name string
 }
 
 // ErrCommandMissing Error type for when the command line has ended with a
 // parent command with no handler, meaning one of its children needed to be
 // chosen instead.
-type ErrCommandMissing struct{}
+type ErrCommandMissing struct {
+	// Cmd Parent Command the command line ended at
+	Cmd *Command
+}
+
+// ErrExampleInvalid Error type for when an Example does not resolve against the
+// tree, returned by ValidateExamples().
+type ErrExampleInvalid struct {
+	// Cmd Command the Example is bound to
+	Cmd *Command
+	// Example Example that is invalid
+	Example *Example
+	// Err Reason the Example is invalid
+	Err error
+}
+
+// ErrExitCode Error type a Handler may return to control the exit code reported
+// by Run(), wrapping an optional underlying error.
+type ErrExitCode struct {
+	// Code Exit code for Run() to return
+	Code int
+	// Err Underlying error, or nil
+	Err error
+}
+
+// ErrTreeInvalid Error type for when Strict is set on the root Command, and the
+// tree fails Validate().
+type ErrTreeInvalid struct {
+	// Cmd Command the tree was validated from
+	Cmd *Command
+	// Problems Problems found within the tree
+	Problems []Problem
+}
+
+// ErrHelpKeyword Error type for when the help keyword search, "help -k", is given
+// without a keyword.
+type ErrHelpKeyword struct {
+	// Cmd Command help was requested for
+	Cmd *Command
+}
+
+// ErrMountConflict Error type for when a subtree cannot be mounted, as a child of
+// the mount point already has the same name or alias.
+type ErrMountConflict struct {
+	// Cmd Command the subtree was to be mounted beneath
+	Cmd *Command
+	// Mounted Command the subtree was to be mounted as
+	Mounted *Command
+	// Source Package mounting the subtree
+	Source string
+	// Existing Child of the mount point with the same name or alias
+	Existing *Command
+}
+
+// ErrMountPoint Error type for when a subtree cannot be mounted, as the mount point
+// does not exist or has a Handler.
+type ErrMountPoint struct {
+	// Cmd Command the mount point was searched for beneath
+	Cmd *Command
+	// Point Space separated path of the mount point
+	Point string
+}
+
+// ErrOptionConflict Error type for when the command line contains more options
+// from an OptionGroupExclusive or OptionGroupExactlyOne group than allowed.
+type ErrOptionConflict struct {
+	// Cmd Command that was about to be executed
+	Cmd *Command
+	// Group OptionGroup that was not satisfied
+	Group *OptionGroup
+	// Options Options from the group that were supplied
+	Options []*Option
+}
+
+// ErrOptionGroupMissing Error type for when the command line contains none of the
+// options from an OptionGroupAtLeastOne or OptionGroupExactlyOne group.
+type ErrOptionGroupMissing struct {
+	// Cmd Command that was about to be executed
+	Cmd *Command
+	// Group OptionGroup that was not satisfied
+	Group *OptionGroup
+}
 
 // ErrOptionMissing Error type for when a required option is missing.
 type ErrOptionMissing struct {
-	data string
+	// Cmd Command that was about to be executed
+	Cmd *Command
+	// Option Required Option that was not supplied
+	Option *Option
 }
 
 // ErrOptionMissingParam Error type for when the command line contains an
 // option that requires a parameter, but one is not specified
 type ErrOptionMissingParam struct {
-	data string
+	// Cmd Command the option was given to
+	Cmd *Command
+	// Arg Option as given on the command line, e.g. "--foo"
+	Arg string
+}
+
+// ErrOptionRequires Error type for when the command line contains an option from
+// an OptionGroupRequires group, without an option it requires.
+type ErrOptionRequires struct {
+	// Cmd Command that was about to be executed
+	Cmd *Command
+	// Group OptionGroup that was not satisfied
+	Group *OptionGroup
+	// Option Option that was supplied
+	Option *Option
+	// Required Option that was required, but not supplied
+	Required *Option
 }
 
 // ErrOptionUnknown Error type for when the command line contains an option,
 // that is not defined in the command tree.
 type ErrOptionUnknown struct {
-	data string
+	// Cmd Command the option was given to
+	Cmd *Command
+	// Arg Option as given on the command line, e.g. "-foo"
+	Arg string
 }
 
 func (e *ErrCallback) Error() string {
-	return fmt.Sprintf("Callback error: %s", e.data)
+	return message(e.Cmd, MsgErrCallback, e.Err)
+}
+
+// Unwrap returns the error returned by the callback.
+func (e *ErrCallback) Unwrap() error {
+	return e.Err
 }
 
 func (e *ErrCallbackPre) Error() string {
-	return fmt.Sprintf("CallbackPre error: %s", e.data)
+	return message(e.Cmd, MsgErrCallbackPre, e.Err)
+}
+
+// Unwrap returns the error returned by the callback.
+func (e *ErrCallbackPre) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrCancelled) Error() string {
+	if e.Signal != nil {
+		return message(e.Cmd, MsgErrCancelledSignal, e.Signal)
+	}
+
+	return message(e.Cmd, MsgErrCancelled, e.Err)
+}
+
+// Unwrap returns the cause of the cancellation.
+func (e *ErrCancelled) Unwrap() error {
+	return e.Err
 }
 
 func (e *ErrCommandError) Error() string {
-	return fmt.Sprintf("Error: %s", e.data)
+	return message(e.Cmd, MsgErrCommandError, e.Err)
+}
+
+// Unwrap returns the error returned by the Handler.
+func (e *ErrCommandError) Unwrap() error {
+	return e.Err
 }
 
 func (e *ErrCommandInvalid) Error() string {
-	return fmt.Sprintf("Invalid subcommand: %s", e.data)
+	return message(e.Cmd, MsgErrCommandInvalid, e.Name)
 }
 
 func (e *ErrCommandMissing) Error() string {
-	return fmt.Sprintf("No subcommand specified")
+	return message(e.Cmd, MsgErrCommandMissing)
+}
+
+func (e *ErrExampleInvalid) Error() string {
+	return message(e.Cmd, MsgErrExampleInvalid, e.Cmd.GetNameChain(), e.Example.Command, e.Err)
+}
+
+// Unwrap returns the reason the Example is invalid.
+func (e *ErrExampleInvalid) Unwrap() error {
+	return e.Err
+}
+
+// NewErrExitCode creates an ErrExitCode, which when returned from a Handler causes
+// Run() to exit with code.  err may be nil, in which case Run() prints nothing.
+func NewErrExitCode(code int, err error) *ErrExitCode {
+	return &ErrExitCode{code, err}
+}
+
+func (e *ErrExitCode) Error() string {
+	if e.Err == nil {
+		return message(nil, MsgErrExitCode, e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrExitCode) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code Run() will use.
+func (e *ErrExitCode) ExitCode() int {
+	return e.Code
+}
+
+func (e *ErrHelpKeyword) Error() string {
+	return message(e.Cmd, MsgSearchKeyword)
+}
+
+func (e *ErrMountConflict) Error() string {
+	existing := e.Existing.GetSource()
+	if existing == "" {
+		existing = "the tree"
+	}
+
+	return message(e.Cmd, MsgErrMountConflict, e.Mounted.Name, e.Source, e.Cmd.GetNameChain(), e.Existing.Name, existing)
+}
+
+func (e *ErrMountPoint) Error() string {
+	return message(e.Cmd, MsgErrMountPoint, strings.TrimSpace(e.Cmd.GetNameChain()+" "+e.Point))
+}
+
+func (e *ErrOptionConflict) Error() string {
+	return message(e.Cmd, MsgErrOptionConflict, optionFlagList(e.Options))
+}
+
+func (e *ErrOptionGroupMissing) Error() string {
+	return message(e.Cmd, MsgErrOptionGroup, optionFlagList(e.Group.Options))
 }
 
 func (e *ErrOptionMissing) Error() string {
-	return fmt.Sprintf("Required option missing: %s", e.data)
+	return message(e.Cmd, MsgErrOptionMissing, e.Option.Name)
 }
 
 func (e *ErrOptionMissingParam) Error() string {
-	return fmt.Sprintf("Missing parameter to option: %s", e.data)
+	return message(e.Cmd, MsgErrOptionMissingArg, e.Arg)
+}
+
+func (e *ErrOptionRequires) Error() string {
+	return message(e.Cmd, MsgErrOptionRequires, optionFlag(e.Option), optionFlag(e.Required))
 }
 
 func (e *ErrOptionUnknown) Error() string {
-	return fmt.Sprintf("Unknown option: %s", e.data)
+	return message(e.Cmd, MsgErrOptionUnknown, e.Arg)
+}
+
+func (e *ErrTreeInvalid) Error() string {
+	var problems []string
+	for _, problem := range e.Problems {
+		problems = append(problems, problem.String())
+	}
+
+	return message(e.Cmd, MsgErrTreeInvalid, strings.Join(problems, "; "))
 }
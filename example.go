@@ -0,0 +1,130 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// An Example represents a sample invocation of a Command, displayed within its
+// help output.
+type Example struct {
+	// Command Full command line of the example, starting with the root name, e.g.
+	//   helloworld say -u --say "Hello there"
+	Command string
+	// Desc Explanation of what the example does
+	Desc string
+}
+
+// NewExample creates a new Example and automatically binds it to the Command.
+//
+// command is the full command line of the example, starting with the name of the
+// root Command.  Single and double quotes may be used to group words into a
+// single argument, as with a shell.
+func (c *Command) NewExample(command string, desc string) *Example {
+	example := &Example{
+		Command: command,
+		Desc:    desc,
+	}
+	c.Examples = append(c.Examples, example)
+
+	return example
+}
+
+// ValidateExamples checks every Example bound to this Command and all of its
+// children resolves against the tree.  Each example must start with the root name,
+// select a Command with a Handler and supply all required options.  As with
+// Resolve(), pre-validation callbacks are run, as they may supply options, but no
+// validation callbacks or handlers are.
+//
+// This is opt-in and is intended to be called from tests, so examples cannot go
+// stale as the tree changes.  All invalid examples are reported, as ErrExampleInvalid
+// errors joined via errors.Join(), or nil is returned if all are valid.
+func (c *Command) ValidateExamples() error {
+	var errs []error
+
+	for _, example := range c.Examples {
+		if err := c.GetRoot().validateExample(example); err != nil {
+			errs = append(errs, &ErrExampleInvalid{c, example, err})
+		}
+	}
+
+	for _, child := range c.Children {
+		if err := child.ValidateExamples(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateExample resolves an Example under this Command, which should be the root.
+func (c *Command) validateExample(example *Example) error {
+	args, err := splitArgs(example.Command)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 || args[0] != c.Name {
+		return fmt.Errorf("does not start with %s", c.Name)
+	}
+
+	// examples must run a Command, rather than display help for the root
+	result, err := c.resolveChecked(context.Background(), args)
+	if err == nil && !result.help && result.data.help {
+		return &ErrCommandMissing{result.data.Cmd}
+	}
+
+	return err
+}
+
+// splitArgs splits a command line into its arguments on whitespace, treating text
+// within single or double quotes as a single argument.  Within double quotes, or
+// outside of quotes, a backslash escapes the following character.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var inarg, escaped bool
+	var quote rune
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inarg = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inarg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inarg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inarg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inarg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape")
+	}
+
+	if inarg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
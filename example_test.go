@@ -95,8 +95,5 @@ func Example() {
 	// Create a required option to hello say, with a parameter
 	cliHelloSomething.NewOption("say", "Thing to say", true).SetRequired()
 
-	if error := cliRoot.Parse(); error != nil {
-		fmt.Printf("%v\n", error)
-		os.Exit(1)
-	}
+	os.Exit(cliRoot.Run(os.Args))
 }
@@ -30,8 +30,14 @@ Once built, it could be called with the following examples:
 	./helloworld say help
 		Outputs: Autogenerated help information
 
+	./helloworld help -tree
+		Outputs: Overview of all commands
+
 	./command say
 		Returns error as "--say" option not specified.
+
+	./command hello -u -lower
+		Returns error as "-u" and "-lower" are mutually exclusive.
 */
 
 import (
@@ -75,9 +81,12 @@ func main() {
 	// Our root command for the tree.  handler is specified as nil, as this
 	// will have children.
 	cliRoot := clicommand.NewCommand("helloworld", "Sample hello world program", nil)
-	// These options are available to all subcommands
-	cliRoot.NewOption("u", "Uppercase output", false)
-	cliRoot.NewOption("lower", "Lowercase output", false)
+	// Allow "helloworld help -tree" to show an overview of every command
+	cliRoot.HelpTree = true
+	// These options are available to all subcommands, but contradict each other
+	optUpper := cliRoot.NewOption("u", "Uppercase output", false)
+	optLower := cliRoot.NewOption("lower", "Lowercase output", false)
+	cliRoot.NewOptionGroup(clicommand.OptionGroupExclusive, optUpper, optLower)
 
 	// Create a hello command off our root object
 	cliRoot.NewCommand("hello", "Says hello world", sayHelloWorld)
@@ -86,8 +95,7 @@ func main() {
 	cliSay := cliRoot.NewCommand("say", "Says something", sayHelloSomething)
 	// Create a required option to hello say, with a parameter
 	cliSay.NewOption("say", "Thing to say", true).SetRequired()
+	cliSay.NewExample(`helloworld say -u --say "This is a test"`, "Says THIS IS A TEST")
 
-	if error := cliRoot.Parse(); error != nil {
-		os.Exit(1)
-	}
+	os.Exit(cliRoot.Run(os.Args))
 }
@@ -0,0 +1,113 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fuzzNames are the names used for commands and options within generated trees,
+// kept short and overlapping so arbitrary command lines often match them
+var fuzzNames = []string{"a", "b", "A", "help", "-", "ab", ""}
+
+// newFuzzTree generates a tree from shape, each byte deciding the children and
+// options of the next Command, breadth first.
+func newFuzzTree(shape []byte) *Command {
+	cmdRoot := NewCommand("root", "root description", nil)
+	cmdRoot.Stdout = io.Discard
+	cmdRoot.Stderr = io.Discard
+
+	queue := []*Command{cmdRoot}
+	for i := 0; i < len(shape) && len(queue) > 0 && i < 64; i++ {
+		cmd := queue[0]
+		queue = queue[1:]
+		b := int(shape[i])
+
+		for j := 0; j < b&0x3 && cmd.Handler == nil; j++ {
+			var handler Handler
+			if (b>>(2+j))&0x1 == 1 {
+				handler = testHandlerFunc
+			}
+			name := fuzzNames[(b+j)%len(fuzzNames)]
+			queue = append(queue, cmd.NewCommand(name, name+" description", handler))
+		}
+
+		for j := 0; j < (b>>4)&0x3; j++ {
+			name := fuzzNames[(b>>2+j)%len(fuzzNames)]
+			option := cmd.NewOption(name, name+" description", (b>>(6+j))&0x1 == 1)
+			if b&0x80 != 0 {
+				cmd.SetOptionRequired(option)
+			}
+		}
+
+		if b&0x40 != 0 && len(cmd.Options) > 0 {
+			cmd.NewOptionGroup(OptionGroupExclusive, cmd.Options...)
+		}
+	}
+
+	return cmdRoot
+}
+
+// fuzzCheckPath checks cmd lies on a real path from the root of the tree.
+func fuzzCheckPath(t *testing.T, root *Command, cmd *Command) {
+	for ; cmd != root; cmd = cmd.Parent {
+		if cmd == nil || cmd.Parent == nil {
+			t.Fatalf("command not beneath the root")
+		}
+
+		var found bool
+		for _, child := range cmd.Parent.Children {
+			found = found || child == cmd
+		}
+		if !found {
+			t.Fatalf("command %q not a child of its parent", cmd.GetNameChain())
+		}
+	}
+}
+
+// fuzzCheckOptions checks every option within data belongs to an Option visible from
+// the selected Command, with a value only if the Option takes a parameter.  A flag
+// and a parameter option may share a name, so either is accepted.
+func fuzzCheckOptions(t *testing.T, data *Data) {
+	for name, value := range data.Options {
+		var found bool
+		for cmd := data.Cmd; cmd != nil; cmd = cmd.Parent {
+			for _, option := range cmd.Options {
+				found = found || option.Name == name && (option.Param || value == "")
+			}
+		}
+
+		if !found {
+			t.Fatalf("option %q with value %q not visible from %q", name, value, data.Cmd.GetNameChain())
+		}
+	}
+}
+
+// FuzzParse feeds arbitrary command lines, split on spaces, into the parser under
+// trees generated from shape.  The parser must not panic, must resolve to a Command
+// within the tree, and must only record options visible from that Command.
+func FuzzParse(f *testing.F) {
+	f.Add([]byte{0x03, 0x01, 0x12}, "a b")
+	f.Add([]byte{0x37, 0xf5}, "-a --b value A help -k a")
+	f.Add([]byte{0x1d, 0x0e}, "- -- --a")
+	f.Add([]byte{0x02}, "help -tree")
+	f.Add([]byte{}, "-no-color help")
+
+	f.Fuzz(func(t *testing.T, shape []byte, line string) {
+		cmdRoot := newFuzzTree(shape)
+		args := append([]string{"root"}, strings.Split(line, " ")...)
+
+		data, _ := cmdRoot.Resolve(args)
+		if data == nil || data.Cmd == nil {
+			t.Fatalf("no command resolved")
+		}
+		fuzzCheckPath(t, cmdRoot, data.Cmd)
+		fuzzCheckOptions(t, data)
+
+		cmdRoot.ParseContext(context.Background(), args)
+	})
+}
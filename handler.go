@@ -14,6 +14,7 @@ package clicommand
 // of supplied options and an array of the supplied parameters.
 //
 // If the Handler function encounters an error it should return this as
-// an error and it will automatically be sent to stderr.  The Handler
-// function should return nil on success.
+// an error and when run via Run() it will automatically be sent to stderr.
+// The Handler function should return nil on success, or an ErrExitCode to
+// control the exit code Run() returns.
 type Handler func(*Data) (err error)
@@ -1,87 +1,447 @@
 package clicommand
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"text/template"
 )
 
-var (
-	cmdHelp = &Command{
-		Handler: helpUsage,
+// DefaultHelpTemplate is the text/template used to render help output, unless
+// overridden by setting HelpTemplate on a Command.  It is executed with HelpData.
+//
+// Within templates, msg formats a message by its Msg* key translated via the
+// Catalog, and rule describes an OptionGroup in the same way.
+const DefaultHelpTemplate = `
+{{.Name}} - {{wrap (len (print .Name " - ")) .Desc}}
+{{.Usage}}
+{{with .Aliases}}{{msg "help.aliases"}} {{join . ", "}}
+{{end}}
+{{with .LongDesc}}{{wrap 0 .}}
+
+{{end}}{{range $section := .Options}}{{heading (msg "help.options" .NameChain)}}
+{{range .Options}}{{row (option (printf "  %2s%s%s" .Prefix .Name .Arg)) $section.Width (print (required .Required) (deprecated .Deprecated) .Desc)}}
+{{end}}
+{{end}}{{with .OptionGroups}}{{heading (msg "help.optionrules")}}
+{{range .}}  {{rule .}}
+{{end}}
+{{end}}{{range $group := .CommandGroups}}{{with .Name}}{{heading (print . ":")}}{{else}}{{heading (msg "help.subcommands")}}{{end}}
+{{range .Commands}}{{row (print "  " .Name) $group.Width (print (deprecated .Deprecated) .Desc)}}
+{{end}}
+{{end}}{{with .Topics}}{{heading (msg "help.topics")}}
+{{range .}}{{row (print "  " .Name) $.TopicsWidth .Desc}}
+{{end}}
+{{end}}{{with .Examples}}{{heading (msg "help.examples")}}
+{{range .}}  {{.Command}}
+{{with .Desc}}      {{wrap 6 .}}
+{{end}}{{end}}
+{{end}}{{if .Parent}}{{heading (msg "help.information")}}
+  '{{.NameTop}} help' .. '{{.NameTop}} <commands>* help' .. '{{.NameTop}} help -k <keyword>'
+
+{{end}}{{with .Footer}}{{wrap 0 .}}
+
+{{end}}`
+
+// HelpData is the data help templates are executed with.
+type HelpData struct {
+	// Cmd Command help is being displayed for
+	Cmd *Command
+	// Name Name of the Command
+	Name string
+	// Desc Description of the Command
+	Desc string
+	// LongDesc Long-form description of the Command
+	LongDesc string
+	// Examples Sample invocations of the Command
+	Examples []*Example
+	// Footer Text shown at the end of help
+	Footer string
+	// NameChain Space separated names of all Command objects from the root
+	NameChain string
+	// NameTop Name of the root Command
+	NameTop string
+	// Usage Usage line, showing the Command chain and its options
+	Usage string
+	// Aliases Alternative names for the Command
+	Aliases []string
+	// Parent Whether the Command is a parent, i.e. has no Handler
+	Parent bool
+	// Commands Child commands, excluding hidden commands, in the order of the tree
+	Commands []*HelpCommand
+	// CommandGroups Child commands split by their Group, with ungrouped commands
+	// first
+	CommandGroups []*HelpCommandGroup
+	// Options Sections of options, one per Command with options from the root down
+	// to the Command, excluding hidden options
+	Options []*HelpOptions
+	// LocalOptions Options bound directly to the Command
+	LocalOptions []*HelpOption
+	// InheritedOptions Options bound to parents of the Command
+	InheritedOptions []*HelpOption
+	// OptionGroups Relationships between options, from the root down to the Command
+	OptionGroups []*OptionGroup
+	// Params Parameters supplied on the command line
+	Params []string
+	// Topics Help topics registered on the tree, only set for the root Command
+	Topics []*Topic
+	// TopicsWidth Width of the column of topic names, including indent
+	TopicsWidth int
+	// Width Width help is wrapped to
+	Width int
+	// CommandsWidth Width of the column of child command names, including indent
+	CommandsWidth int
+}
+
+// HelpCommand is the data for a child command within HelpData.
+type HelpCommand struct {
+	// Cmd Child Command
+	Cmd *Command
+	// Name Name of the child
+	Name string
+	// Desc Description of the child
+	Desc string
+	// Aliases Alternative names for the child
+	Aliases []string
+	// Deprecated Deprecation details, or nil
+	Deprecated *Deprecation
+}
+
+// HelpCommandGroup is the data for a group of child commands within HelpData.
+type HelpCommandGroup struct {
+	// Name Name of the group, empty for commands without a group
+	Name string
+	// Commands Child commands within the group
+	Commands []*HelpCommand
+	// Width Width of the column of child command names, including indent
+	Width int
+}
+
+// HelpOptions is the data for a section of options within HelpData, being those
+// bound to a single Command.
+type HelpOptions struct {
+	// Cmd Command the options are bound to
+	Cmd *Command
+	// NameChain Space separated names of all Command objects from the root
+	NameChain string
+	// Options Options bound to the Command
+	Options []*HelpOption
+	// Width Width of the column of option names, including indent
+	Width int
+}
+
+// HelpOption is the data for an option within HelpData.
+type HelpOption struct {
+	// Option Option being displayed
+	Option *Option
+	// Prefix Dashes the option is specified with, "-" or "--"
+	Prefix string
+	// Name Name of the option
+	Name string
+	// Arg Suffix showing the option takes a parameter, " <arg>" or empty
+	Arg string
+	// Desc Description of the option
+	Desc string
+	// Required Whether the option is required for the Command help is displayed for
+	Required bool
+	// Deprecated Deprecation details, or nil
+	Deprecated *Deprecation
+}
+
+// helpFuncs returns the functions available within help templates, translating
+// messages for the tree cmd is within, wrapping text to the given width and styling
+// it with palette, which may be nil.
+func helpFuncs(cmd *Command, width int, palette *Palette) template.FuncMap {
+	return template.FuncMap{
+		"join": strings.Join,
+		"msg": func(key string, args ...interface{}) string {
+			return message(cmd, key, args...)
+		},
+		"rule": func(group *OptionGroup) string {
+			return group.describe(cmd)
+		},
+		"deprecated": func(deprecation *Deprecation) string {
+			return helpDeprecatedPrefix(cmd, deprecation)
+		},
+		"required": func(required bool) string {
+			return palette.required(helpRequiredPrefix(cmd, required))
+		},
+		"heading": palette.heading,
+		"option":  palette.option,
+		"wrap": func(indent int, text string) string {
+			return wrapText(text, indent, width)
+		},
+		"row": func(label string, colwidth int, text string) string {
+			return wrapRow(label, colwidth, text, width)
+		},
 	}
-)
+}
 
 func helpError(data *Data, err error) error {
-	helpOutput(data, true)
+	root := data.Cmd.GetRoot()
+	out := root.getStderr()
+	palette := root.getPalette(out, data)
+	verbosity := root.getErrorVerbosity(err)
 
-	fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-	fmt.Fprintf(os.Stderr, "\n")
-	fmt.Fprintf(os.Stderr, "For help information, run: %s help\n", data.Cmd.GetNameChain())
+	switch verbosity {
+	case ErrorsHelp:
+		helpOutput(data, true)
+	case ErrorsUsage:
+		fmt.Fprintf(out, "%s %s\n", palette.heading(message(data.Cmd, MsgHelpUsage)), helpCommandShort(data, data.Cmd, data.Cmd))
+	}
+
+	fmt.Fprintf(out, "%s\n", palette.error(message(data.Cmd, MsgHelpError, err)))
+
+	if verbosity != ErrorsOnly {
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", message(data.Cmd, MsgHelpRun, data.nameChain(data.Cmd)))
+	}
 
 	return err
 }
 
+// helpUsage is the handler for the help command.  Parameters following help select
+// either a keyword search via "-k <keyword>", an overview of the tree via "-tree"
+// if enabled, or a Topic, otherwise help for the selected Command is displayed.
 func helpUsage(data *Data) error {
-	helpOutput(data, false)
-	return nil
+	if len(data.Params) > 0 && data.Params[0] == "-k" {
+		return helpSearch(data, strings.Join(data.Params[1:], " "))
+	}
+
+	if len(data.Params) > 0 && data.Params[0] == "-tree" && data.Cmd.GetRoot().HelpTree {
+		return data.Cmd.writeTree(data.Cmd.GetRoot().getStdout(), false)
+	}
+
+	if len(data.Params) > 0 {
+		if topic := data.Cmd.GetTopic(data.Params[0]); topic != nil {
+			return helpTopic(data, topic)
+		}
+	}
+
+	return helpOutput(data, false)
 }
 
-func helpOutput(data *Data, stderr bool) {
-	out := os.Stdout
+func helpOutput(data *Data, stderr bool) error {
+	cmd := data.Cmd
+
+	out := cmd.GetRoot().getStdout()
 	if stderr {
-		out = os.Stderr
+		out = cmd.GetRoot().getStderr()
 	}
 
+	helpdata := newHelpData(data)
+	helpdata.Width = cmd.GetRoot().getHelpWidth(out)
+
+	return helpRender(out, helpdata, cmd.GetRoot().getPalette(out, data))
+}
+
+// WriteHelp writes help for the Command to w, exactly as the help command displays
+// it though without colour, wrapped to HelpWidth if set on the root Command.
+func (c *Command) WriteHelp(w io.Writer) error {
+	data := &Data{
+		Ctx:     context.Background(),
+		Cmd:     c,
+		Options: make(map[string]string),
+		help:    true,
+	}
+
+	helpdata := newHelpData(data)
+	helpdata.Width = c.GetRoot().getHelpWidth(w)
+
+	return helpRender(w, helpdata, nil)
+}
+
+// helpRender executes the help template for the Command within helpdata, styling
+// output with palette which may be nil.
+func helpRender(out io.Writer, helpdata *HelpData, palette *Palette) error {
+	tmpl, err := template.New("help").Funcs(helpFuncs(helpdata.Cmd, helpdata.Width, palette)).Parse(helpdata.Cmd.getHelpTemplate())
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(out, helpdata)
+}
+
+// getHelpWidth returns the width help written to out should be wrapped to.  This
+// is HelpWidth if set, otherwise the width of the terminal if out is a terminal,
+// otherwise the COLUMNS environment variable, falling back to 80.  This should only
+// be called on the root Command.
+func (c *Command) getHelpWidth(out io.Writer) int {
+	if c.HelpWidth > 0 {
+		return c.HelpWidth
+	}
+
+	if width, ok := terminalWidth(out); ok && width > 0 {
+		return width
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return 80
+}
+
+// getHelpTemplate finds the help template for the Command, searching up the tree
+// to the root and falling back to DefaultHelpTemplate.
+func (c *Command) getHelpTemplate() string {
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		if cmd.HelpTemplate != "" {
+			return cmd.HelpTemplate
+		}
+	}
+
+	return DefaultHelpTemplate
+}
+
+// newHelpData builds the HelpData for the Command selected within data.
+func newHelpData(data *Data) *HelpData {
 	cmd := data.Cmd
 
-	fmt.Fprintf(out, "\n")
-	fmt.Fprintf(out, "%s - %s\n", cmd.Name, cmd.Desc)
-	fmt.Fprintf(out, "%s\n", helpCommandShort(cmd))
-	fmt.Fprintf(out, "\n")
+	name := cmd.Name
+	if cmd == data.invoked {
+		name = data.invokedName
+	}
 
-	helpOptionsRecurseRev(cmd)
+	helpdata := &HelpData{
+		Cmd:          cmd,
+		Name:         name,
+		Desc:         cmd.GetDesc(),
+		LongDesc:     cmd.LongDesc,
+		Examples:     cmd.Examples,
+		Footer:       cmd.Footer,
+		NameChain:    data.nameChain(cmd),
+		NameTop:      data.nameTop(),
+		Usage:        helpCommandShort(data, cmd, cmd),
+		Aliases:      cmd.Aliases,
+		Parent:       cmd.Handler == nil,
+		OptionGroups: helpOptionGroupsRecurseRev(cmd),
+		Params:       data.Params,
+	}
 
-	if len(cmd.Children) > 0 {
-		fmt.Fprintf(out, "Available subcommands:\n")
-		for _, v := range cmd.Children {
-			fmt.Fprintf(out, "  %-12s %s\n", v.Name, v.Desc)
+	if cmd.Parent == nil {
+		helpdata.Topics = cmd.Topics
+		for _, topic := range cmd.Topics {
+			helpdata.TopicsWidth = helpMax(helpdata.TopicsWidth, "  "+topic.Name)
 		}
-		fmt.Fprintf(out, "\n")
 	}
 
-	if cmd.Handler == nil {
-		fmt.Fprintf(out, "For help information run:\n")
-		fmt.Fprintf(out, "  '%s help' .. '%s <commands>* help'\n",
-			cmd.GetNameTop(), cmd.GetNameTop())
-		fmt.Fprintf(out, "\n")
+	groups := make(map[string]*HelpCommandGroup)
+	for _, child := range helpChildren(cmd) {
+		helpcmd := &HelpCommand{
+			Cmd:        child,
+			Name:       child.Name,
+			Desc:       child.GetDesc(),
+			Aliases:    child.Aliases,
+			Deprecated: child.Deprecated,
+		}
+
+		group, ok := groups[child.Group]
+		if !ok {
+			group = &HelpCommandGroup{Name: child.Group}
+			groups[child.Group] = group
+		}
+		group.Width = helpMax(group.Width, "  "+child.Name)
+		group.Commands = append(group.Commands, helpcmd)
+
+		helpdata.CommandsWidth = helpMax(helpdata.CommandsWidth, "  "+child.Name)
+		helpdata.Commands = append(helpdata.Commands, helpcmd)
 	}
-}
 
-func helpCommandShort(cmd *Command) string {
-	var params []string
+	for _, name := range cmd.GetGroups() {
+		if group, ok := groups[name]; ok {
+			helpdata.CommandGroups = append(helpdata.CommandGroups, group)
+		}
+	}
 
-	for _, option := range cmd.Options {
-		params = append([]string{helpCommandShortOption(option)}, params...)
+	// options from the Command selected by the program name and its parents are
+	// listed together under the invoked name
+	var invokedSection *HelpOptions
+	for optcmd := cmd; optcmd != nil; optcmd = optcmd.Parent {
+		options := helpOptions(cmd, optcmd)
+		if len(options) == 0 {
+			continue
+		}
+
+		if optcmd == cmd {
+			helpdata.LocalOptions = options
+		} else {
+			helpdata.InheritedOptions = append(options, helpdata.InheritedOptions...)
+		}
+
+		if invokedSection != nil {
+			invokedSection.Options = append(options, invokedSection.Options...)
+			continue
+		}
+
+		section := &HelpOptions{
+			Cmd:       optcmd,
+			NameChain: data.nameChain(optcmd),
+			Options:   options,
+		}
+		if data.invokedBeneath(optcmd) {
+			section.Cmd = data.invoked
+			section.NameChain = data.invokedName
+			invokedSection = section
+		}
+
+		helpdata.Options = append([]*HelpOptions{section}, helpdata.Options...)
+	}
+
+	for _, section := range helpdata.Options {
+		for _, option := range section.Options {
+			section.Width = helpMax(section.Width, fmt.Sprintf("  %2s%s%s", option.Prefix, option.Name, option.Arg))
+		}
 	}
 
-	params = append(params, cmd.Name)
+	return helpdata
+}
 
-	if cmd.Parent != nil {
-		params = append(params, helpCommandShort(cmd.Parent))
+// helpCommandShort builds the usage line for cmd, with options marked as required
+// where they are required for the selected leaf Command.  If the program was invoked
+// via a multi-call name, the line starts from that name, followed by the options of
+// the parents of the invoked Command.
+func helpCommandShort(data *Data, leaf *Command, cmd *Command) string {
+	var params []string
+
+	if cmd == data.invoked {
+		params = append(params, data.invokedName)
+		for _, parent := range helpParentsRev(cmd) {
+			for _, option := range helpOptionsShown(parent) {
+				params = append(params, helpCommandShortOption(leaf, option))
+			}
+		}
+	} else {
+		if cmd.Parent != nil {
+			params = append(params, helpCommandShort(data, leaf, cmd.Parent))
+		}
+		params = append(params, cmd.Name)
 	}
 
-	for i, j := 0, len(params)-1; i < j; i, j = i+1, j-1 {
-		params[i], params[j] = params[j], params[i]
+	for _, option := range helpOptionsShown(cmd) {
+		params = append(params, helpCommandShortOption(leaf, option))
 	}
 
 	return strings.Join(params, " ")
 }
 
-func helpCommandShortOption(option *Option) string {
+// helpParentsRev returns the parents of cmd, from the root down.
+func helpParentsRev(cmd *Command) []*Command {
+	var parents []*Command
+	for parent := cmd.Parent; parent != nil; parent = parent.Parent {
+		parents = append([]*Command{parent}, parents...)
+	}
+
+	return parents
+}
+
+func helpCommandShortOption(leaf *Command, option *Option) string {
 	var optstr string
+	var required = leaf.GetOptionRequired(option)
 
-	if !option.Required {
+	if !required {
 		optstr += "["
 	}
 
@@ -91,45 +451,98 @@ func helpCommandShortOption(option *Option) string {
 		optstr += "-" + option.Name
 	}
 
-	if !option.Required {
+	if !required {
 		optstr += "]"
 	}
 
 	return optstr
 }
 
-func helpOptionsRecurseRev(cmd *Command) {
-	if cmd.Parent != nil {
-		helpOptionsRecurseRev(cmd.Parent)
+// helpOptions builds the HelpOption entries for the options bound to cmd, marking
+// them as required where they are required for the selected leaf Command.
+func helpOptions(leaf *Command, cmd *Command) []*HelpOption {
+	var options []*HelpOption
+
+	for _, option := range helpOptionsShown(cmd) {
+		helpoption := &HelpOption{
+			Option:     option,
+			Prefix:     "-",
+			Name:       option.Name,
+			Desc:       option.GetDesc(leaf),
+			Required:   leaf.GetOptionRequired(option),
+			Deprecated: option.Deprecated,
+		}
+
+		if option.Param {
+			helpoption.Prefix = "--"
+			helpoption.Arg = " <arg>"
+		}
+
+		options = append(options, helpoption)
 	}
 
-	helpOptions(cmd)
+	return options
 }
 
-func helpOptions(cmd *Command) {
-	if len(cmd.Options) == 0 {
-		return
+func helpOptionGroupsRecurseRev(cmd *Command) []*OptionGroup {
+	var groups []*OptionGroup
+	if cmd.Parent != nil {
+		groups = helpOptionGroupsRecurseRev(cmd.Parent)
 	}
 
-	fmt.Printf("%s options:\n", cmd.GetNameChain())
-	for _, option := range cmd.Options {
-		var opttype string
-		var optsuffix string
-		var descprefix string
+	return append(groups, cmd.OptionGroups...)
+}
 
-		if option.Param {
-			opttype += "--"
-			optsuffix += " <arg>"
-		} else {
-			opttype += "-"
+// helpChildren returns the children of cmd which are not hidden, in the order
+// configured for the tree.
+func helpChildren(cmd *Command) []*Command {
+	var children []*Command
+	for _, child := range cmd.GetChildren() {
+		if !child.Hidden {
+			children = append(children, child)
 		}
+	}
+
+	return children
+}
 
-		if option.Required {
-			descprefix += "Required: "
+// helpOptionsShown returns the Options bound to cmd which are not hidden.
+func helpOptionsShown(cmd *Command) []*Option {
+	var options []*Option
+	for _, option := range cmd.Options {
+		if !option.Hidden {
+			options = append(options, option)
 		}
+	}
+
+	return options
+}
+
+// helpMax returns the larger of width and the width of label.
+func helpMax(width int, label string) int {
+	if labelwidth := textWidth(label); labelwidth > width {
+		return labelwidth
+	}
+
+	return width
+}
+
+func helpRequiredPrefix(cmd *Command, required bool) string {
+	if required {
+		return message(cmd, MsgHelpRequired)
+	}
+
+	return ""
+}
+
+func helpDeprecatedPrefix(cmd *Command, deprecation *Deprecation) string {
+	if deprecation == nil {
+		return ""
+	}
 
-		fmt.Printf("  %2s%-20s %s\n", opttype, option.Name+optsuffix, descprefix+option.Desc)
+	if deprecation.Replacement != "" {
+		return message(cmd, MsgHelpDeprecatedUse, deprecation.Replacement)
 	}
 
-	fmt.Printf("\n")
+	return message(cmd, MsgHelpDeprecated)
 }
@@ -0,0 +1,277 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newHelpTree creates a root with a child and options at both levels, writing
+// all output to the returned buffer
+func newHelpTree() (*Command, *bytes.Buffer) {
+	var out bytes.Buffer
+
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.HelpWidth = 80
+	cmdRoot.Stdout = &out
+	cmdRoot.Stderr = &out
+	cmdRoot.newOption()
+	cmdRoot.newCommandChild(testHandlerFunc).SetAliases("alias").NewOption("param", "param description", true)
+
+	return cmdRoot, &out
+}
+
+// helpOutput testing, validate the default template output
+func TestHelpOutput(t *testing.T) {
+	cmdRoot, out := newHelpTree()
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "help"})
+
+	assert.Equal(t, `
+child - child description
+root [-option] child [--param <param>]
+Aliases: alias
+
+root options:
+   -option option description
+
+root child options:
+  --param <arg> param description
+
+`, out.String())
+}
+
+// helpOutput testing, validate templates are inherited from parents and can be
+// overridden per command
+func TestHelpTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot, out := newHelpTree()
+	cmdRoot.SetHelpTemplate("{{.NameChain}}:{{range .InheritedOptions}} {{.Name}}{{end}}\n")
+
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "help"})
+	assert.Equal("root child: option\n", out.String())
+
+	out.Reset()
+	cmdRoot.GetCommand(cmdChildName).SetHelpTemplate("{{range .LocalOptions}}{{.Prefix}}{{.Name}}{{end}}\n")
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "help"})
+	assert.Equal("--param\n", out.String())
+}
+
+// helpOutput testing, validate columns align to the longest name in each section
+// and descriptions wrap with a hanging indent
+func TestHelpOutputWrap(t *testing.T) {
+	cmdRoot, out := newHelpTree()
+	cmdRoot.HelpWidth = 40
+	cmdRoot.NewCommand("longername", "a description long enough that it needs to wrap", testHandlerFunc)
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "help"})
+
+	assert.Contains(t, out.String(), `Available subcommands:
+  child      child description
+  longername a description long enough
+             that it needs to wrap
+`)
+}
+
+// wrapRow testing, validate labels wider than the column push the text onto the
+// next line
+func TestWrapRow(t *testing.T) {
+	assert.Equal(t, "  a-very-long-label\n           text", wrapRow("  a-very-long-label", 30, "text", 20))
+	assert.Equal(t, "  label text", wrapRow("  label", 7, "text", 80))
+}
+
+// helpOutput testing, validate long descriptions, examples and footers are shown
+func TestHelpOutputExamples(t *testing.T) {
+	cmdRoot, out := newHelpTree()
+	cmdChild := cmdRoot.GetCommand(cmdChildName).SetLongDesc("long description").SetFooter("footer")
+	cmdChild.NewExample("root child --param value", "example description")
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "help"})
+
+	assert.Equal(t, `
+child - child description
+root [-option] child [--param <param>]
+Aliases: alias
+
+long description
+
+root options:
+   -option option description
+
+root child options:
+  --param <arg> param description
+
+Examples:
+  root child --param value
+      example description
+
+footer
+
+`, out.String())
+}
+
+// ValidateExamples testing, validate examples are resolved against the tree
+func TestValidateExamples(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot, _ := newHelpTree()
+	cmdChild := cmdRoot.GetCommand(cmdChildName)
+	cmdChild.GetOption("param", true).SetRequired()
+
+	cmdChild.NewExample("root alias -option --param 'quoted value' extra", "valid")
+	cmdRoot.NewExample("root help", "valid help")
+	assert.Nil(cmdRoot.ValidateExamples())
+
+	cmdChild.NewExample("root child", "missing required option")
+	cmdChild.NewExample("root missing", "invalid command")
+	cmdRoot.NewExample("other child --param value", "wrong root")
+
+	err := cmdRoot.ValidateExamples()
+	var errMissing *ErrOptionMissing
+	var errInvalid *ErrCommandInvalid
+	assert.ErrorAs(err, &errMissing)
+	assert.ErrorAs(err, &errInvalid)
+	assert.Contains(err.Error(), "does not start with root")
+}
+
+// ValidateExamples testing, validate pre-validation callbacks may supply required
+// options, but examples must still select a Command with a Handler
+func TestValidateExamplesCallbackPre(t *testing.T) {
+	cmdRoot, _ := newHelpTree()
+	cmdChild := cmdRoot.GetCommand(cmdChildName)
+	cmdChild.GetOption("param", true).SetRequired()
+	cmdRoot.BindCallbackPre(func(data *Data) error {
+		data.Options["param"] = "from environment"
+		return nil
+	})
+
+	cmdChild.NewExample("root child", "param from environment")
+	assert.Nil(t, cmdRoot.ValidateExamples())
+
+	cmdRoot.NewExample("root", "no command")
+	var errMissing *ErrCommandMissing
+	assert.ErrorAs(t, cmdRoot.ValidateExamples(), &errMissing)
+}
+
+// splitArgs testing, validate quoting and escaping
+func TestSplitArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	args, err := splitArgs(`root  say --say "hello 'there'" 'a \ b' c\ d`)
+	assert.Nil(err)
+	assert.Equal([]string{"root", "say", "--say", "hello 'there'", `a \ b`, "c d"}, args)
+
+	_, err = splitArgs(`root "unterminated`)
+	assert.NotNil(err)
+}
+
+// helpOutput testing, validate children are listed under their groups and in
+// the configured order
+func TestHelpOutputGroups(t *testing.T) {
+	cmdRoot, out := newHelpTree()
+	cmdRoot.SetCommandOrder(OrderAlphabetical)
+	cmdRoot.NewCommand("zeta", "zeta description", testHandlerFunc).SetGroup("Resource commands")
+	cmdRoot.NewCommand("users", "users description", testHandlerFunc).SetGroup("Admin")
+	cmdRoot.NewCommand("alpha", "alpha description", testHandlerFunc).SetGroup("Resource commands")
+	cmdRoot.NewCommand("beta", "beta description", testHandlerFunc)
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "help"})
+
+	assert.Contains(t, out.String(), `Available subcommands:
+  beta  beta description
+  child child description
+
+Resource commands:
+  alpha alpha description
+  zeta  zeta description
+
+Admin:
+  users users description
+`)
+}
+
+// helpRender testing, validate colour is applied to headings and options without
+// affecting alignment
+func TestHelpRenderPalette(t *testing.T) {
+	assert := assert.New(t)
+
+	var out bytes.Buffer
+	cmdRoot, _ := newHelpTree()
+	cmdRoot.NewOption("long", "long description", false).SetRequired()
+
+	helpdata := newHelpData(&Data{Cmd: cmdRoot})
+	helpdata.Width = 80
+	assert.Nil(helpRender(&out, helpdata, DefaultPalette))
+
+	assert.Contains(out.String(), "\x1b[1mroot options:\x1b[0m\n")
+	assert.Contains(out.String(), "\x1b[36m   -option\x1b[0m option description\n")
+	assert.Contains(out.String(), "\x1b[36m   -long\x1b[0m   \x1b[33mRequired: \x1b[0mlong description\n")
+}
+
+// getPalette testing, validate colour is disabled when not writing to a terminal,
+// or by NO_COLOR and the -no-color option
+func TestGetPalette(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot, out := newHelpTree()
+	assert.Nil(cmdRoot.getPalette(out, &Data{}))
+
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "-no-color", "help"})
+	assert.NotContains(out.String(), "Unknown option")
+
+	t.Setenv("NO_COLOR", "1")
+	assert.Nil(cmdRoot.getPalette(os.Stdout, &Data{}))
+}
+
+// helpError testing, validate the tree default and per error type verbosity
+func TestHelpErrorVerbosity(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot, out := newHelpTree()
+	cmdRoot.SetErrorVerbosity(ErrorsUsage)
+	cmdRoot.SetErrorVerbosity(ErrorsOnly, &ErrCommandInvalid{})
+
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "-unknown"})
+	assert.Equal(`Usage: root [-option] child [--param <param>]
+Error: Unknown option: -unknown
+
+For help information, run: root child help
+`, out.String())
+
+	out.Reset()
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "invalid"})
+	assert.Equal("Error: Invalid subcommand: invalid\n", out.String())
+}
+
+// helpUsage testing, validate keyword searches list matching commands across the
+// whole tree, and topics are displayed and listed
+func TestHelpSearchTopics(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot, out := newHelpTree()
+	cmdRoot.NewCommand("api", "api description", nil).NewCommand("get", "fetch a child", testHandlerFunc)
+	cmdRoot.NewCommand("secret", "secret child", testHandlerFunc).SetHidden()
+	cmdRoot.NewTopic("environment", "Environment variables", "ROOT_CHILD is read")
+
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "api", "help", "-k", "CHILD"})
+	assert.Equal(`
+Commands matching 'CHILD':
+  root child   child description
+  root api get fetch a child
+
+Help topics matching 'CHILD':
+  environment Environment variables
+
+`, out.String())
+
+	out.Reset()
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "help", "environment"})
+	assert.Equal("\nenvironment - Environment variables\n\nROOT_CHILD is read\n\n", out.String())
+
+	out.Reset()
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "help"})
+	assert.Contains(out.String(), "Help topics:\n  environment Environment variables\n")
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+// Package hook gives clicommandtest access to internals of clicommand, without
+// adding them to its public API.
+package hook
+
+import (
+	"context"
+)
+
+// ParseContext parses args under root exactly as Command.ParseContext does,
+// additionally returning the Data passed to the callbacks and Handler.  root is a
+// *clicommand.Command and the Data a *clicommand.Data.  It is set by clicommand.
+var ParseContext func(ctx context.Context, root interface{}, args []string) (interface{}, error)
@@ -0,0 +1,220 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// lookupIndex holds the index of a Command, which is rebuilt whenever it is used
+// after the tree has changed.  The generation of the root Command is incremented
+// whenever the tree is changed via its methods in a way which may affect
+// GetCommand() or GetOption(), invalidating the index of every Command within it.
+type lookupIndex struct {
+	generation atomic.Uint64
+	tables     atomic.Pointer[lookupTables]
+}
+
+// lookupTables maps names to the children of a Command, and to the options visible
+// from it, as they were at generation of the root index.
+type lookupTables struct {
+	root       *lookupIndex
+	generation uint64
+	commands   map[string]lookupCommandEntry
+	options    map[lookupOptionKey]lookupOptionEntry
+}
+
+// lookupCommandEntry records a child Command and its position within Children, so
+// a hit can be checked against the tree as it is now.
+type lookupCommandEntry struct {
+	cmd   *Command
+	index int
+}
+
+// lookupOptionEntry records an Option along with the Command it was found bound to
+// and its position within the Options of that Command.
+type lookupOptionEntry struct {
+	option *Option
+	owner  *Command
+	index  int
+}
+
+// lookupOptionKey identifies an Option by its name, in lower case, and type.
+type lookupOptionKey struct {
+	name  string
+	param bool
+}
+
+// invalidateLookup invalidates the index of every Command within the tree.
+func (c *Command) invalidateLookup() {
+	if root := c.GetRoot(); root.lookup != nil {
+		root.lookup.generation.Add(1)
+	}
+}
+
+// getLookupTables returns the index of the Command, building it if it is missing or
+// stale, or nil if the Command or the root were not created via NewCommand().
+func (c *Command) getLookupTables() *lookupTables {
+	root := c.GetRoot()
+	if c.lookup == nil || root.lookup == nil {
+		return nil
+	}
+
+	generation := root.lookup.generation.Load()
+	if tables := c.lookup.tables.Load(); tables != nil && tables.root == root.lookup && tables.generation == generation {
+		return tables
+	}
+
+	tables := &lookupTables{
+		root:       root.lookup,
+		generation: generation,
+		commands:   make(map[string]lookupCommandEntry),
+		options:    make(map[lookupOptionKey]lookupOptionEntry),
+	}
+
+	// names take precedence over aliases, then the first child wins
+	for i, cmd := range c.Children {
+		if key := strings.ToLower(cmd.Name); tables.commands[key].cmd == nil {
+			tables.commands[key] = lookupCommandEntry{cmd, i}
+		}
+	}
+	for i, cmd := range c.Children {
+		for _, alias := range cmd.Aliases {
+			if key := strings.ToLower(alias); tables.commands[key].cmd == nil {
+				tables.commands[key] = lookupCommandEntry{cmd, i}
+			}
+		}
+	}
+
+	// options bound closer to the Command shadow those bound to its parents, then
+	// the first option bound to each Command wins
+	var chain []*Command
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		chain = append([]*Command{cmd}, chain...)
+	}
+	for _, cmd := range chain {
+		local := make(map[lookupOptionKey]bool)
+		for i, option := range cmd.Options {
+			key := lookupOptionKey{strings.ToLower(option.Name), option.Param}
+			if !local[key] {
+				local[key] = true
+				tables.options[key] = lookupOptionEntry{option, cmd, i}
+			}
+		}
+	}
+
+	c.lookup.tables.Store(tables)
+	return tables
+}
+
+// resetLookup discards the index of the Command, so it is rebuilt on next use.  This
+// is used once a lookup shows the fields of the tree were changed directly.
+func (c *Command) resetLookup() {
+	if c.lookup != nil {
+		c.lookup.tables.Store(nil)
+	}
+}
+
+// lookupCommand finds a child Command via the index, checking the hit against the
+// tree as it is now.  ok is false if there is no index, the name is not in it, or the
+// hit is stale, in which case the caller must scan the children instead.
+func (c *Command) lookupCommand(name string) (cmd *Command, ok bool) {
+	tables := c.getLookupTables()
+	if tables == nil {
+		return nil, false
+	}
+
+	entry := tables.commands[strings.ToLower(name)]
+	if entry.cmd == nil {
+		return nil, false
+	}
+
+	if entry.index < len(c.Children) && c.Children[entry.index] == entry.cmd {
+		if strings.EqualFold(entry.cmd.Name, name) {
+			return entry.cmd, true
+		}
+
+		// an alias only wins if no child has it as a name
+		for _, alias := range entry.cmd.Aliases {
+			if strings.EqualFold(alias, name) {
+				for _, child := range c.Children {
+					if strings.EqualFold(child.Name, name) {
+						c.resetLookup()
+						return nil, false
+					}
+				}
+				return entry.cmd, true
+			}
+		}
+	}
+
+	c.resetLookup()
+	return nil, false
+}
+
+// scanCommand finds a child Command without the index, names taking precedence
+// over aliases.
+func (c *Command) scanCommand(name string) *Command {
+	for _, cmd := range c.Children {
+		if strings.EqualFold(cmd.Name, name) {
+			return cmd
+		}
+	}
+
+	for _, cmd := range c.Children {
+		for _, alias := range cmd.Aliases {
+			if strings.EqualFold(alias, name) {
+				return cmd
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupOption finds an Option visible from the Command via the index, checking the
+// hit against the tree as it is now.  ok is false if there is no index, the option
+// is not in it, or the hit is stale, in which case the caller must search the tree
+// instead.
+func (c *Command) lookupOption(name string, param bool) (option *Option, ok bool) {
+	tables := c.getLookupTables()
+	if tables == nil {
+		return nil, false
+	}
+
+	entry := tables.options[lookupOptionKey{strings.ToLower(name), param}]
+	if entry.option == nil {
+		return nil, false
+	}
+
+	if entry.index < len(entry.owner.Options) && entry.owner.Options[entry.index] == entry.option &&
+		strings.EqualFold(entry.option.Name, name) && entry.option.Param == param {
+		// the owner must still be within the chain, with nothing beneath it
+		// shadowing the option
+		for cmd := c; cmd != nil; cmd = cmd.Parent {
+			if cmd == entry.owner {
+				return entry.option, true
+			}
+			if cmd.getOptionLocal(name, param) != nil {
+				break
+			}
+		}
+	}
+
+	c.resetLookup()
+	return nil, false
+}
+
+// scanOption finds an Option visible from the Command without the index, searching
+// the entire way up the tree to the root if necessary.
+func (c *Command) scanOption(name string, param bool) *Option {
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		if option := cmd.getOptionLocal(name, param); option != nil {
+			return option
+		}
+	}
+
+	return nil
+}
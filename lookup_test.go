@@ -0,0 +1,185 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// GetCommand testing, validate indexed lookups follow changes to the tree
+func TestLookupCommand(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdGet := cmdApi.GetCommand("GET")
+	assert.Equal(t, "get", cmdGet.Name)
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("fetch"))
+
+	// names take precedence over aliases
+	cmdFetch := cmdApi.NewCommand("fetch", "fetch description", testHandlerFunc)
+	assert.Equal(t, cmdFetch, cmdApi.GetCommand("fetch"))
+
+	cmdGet.SetAliases("show")
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("show"))
+
+	cmdApi.UnbindCommand(cmdFetch)
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("fetch"))
+
+	cmdGet.SetName("read")
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("read"))
+	assert.Nil(t, cmdApi.GetCommand("get"))
+
+	// renaming to the alias of a sibling takes precedence over the alias
+	cmdDelete := cmdApi.GetCommand("delete").SetName("fetch")
+	assert.Equal(t, cmdDelete, cmdApi.GetCommand("fetch"))
+
+	// commands created without NewCommand are not indexed
+	cmdLiteral := &Command{Name: "literal"}
+	cmdLiteral.BindCommand(&Command{Name: "child", Handler: testHandlerFunc})
+	assert.NotNil(t, cmdLiteral.GetCommand("CHILD"))
+}
+
+// GetOption testing, validate indexed lookups follow changes to the tree
+func TestLookupOption(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdDelete := cmdRoot.FindPath("api", "delete")
+	optVerbose := cmdRoot.GetOption("verbose", false)
+	assert.Equal(t, optVerbose, cmdDelete.GetOption("VERBOSE", false))
+	assert.Nil(t, cmdDelete.GetOption("verbose", true))
+
+	optShadow := cmdDelete.NewOption("verbose", "shadows root", false)
+	assert.Equal(t, optShadow, cmdDelete.GetOption("verbose", false))
+	assert.Equal(t, optVerbose, cmdRoot.GetOption("verbose", false))
+
+	cmdDelete.UnbindOption(optShadow)
+	assert.Equal(t, optVerbose, cmdDelete.GetOption("verbose", false))
+
+	optQuiet := cmdDelete.NewOption("quiet", "renamed to shadow root", false)
+	optQuiet.SetName("verbose")
+	assert.Equal(t, optQuiet, cmdDelete.GetOption("verbose", false))
+	cmdDelete.UnbindOption(optQuiet)
+
+	cmdRoot.UnbindOption(optVerbose)
+	assert.Nil(t, cmdDelete.GetOption("verbose", false))
+}
+
+// GetCommand testing, validate each tree has its own index, and subtrees moved
+// between trees see changes made elsewhere
+func TestLookupTrees(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	tables := cmdApi.getLookupTables()
+
+	// changes to other trees do not invalidate the index
+	cmdRoot.Clone().GetCommand("api").NewCommand("list", "list description", testHandlerFunc)
+	newSpecTree()
+	assert.Same(t, tables, cmdApi.getLookupTables())
+
+	cmdRoot.UnbindCommand(cmdApi)
+	assert.NotNil(t, cmdApi.GetCommand("get"))
+
+	other := newSpecTree()
+	other.GetCommand("api").SetName("remote").BindCommand(cmdApi)
+	cmdApi.NewCommand("list", "list description", testHandlerFunc)
+	other.GetCommand("remote").UnbindCommand(cmdApi)
+	assert.NotNil(t, cmdApi.GetCommand("list"))
+}
+
+// GetCommand and GetOption testing, validate lookups follow changes made directly
+// to the fields of the tree after it has been used
+func TestLookupDirect(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdGet := cmdApi.GetCommand("get")
+	cmdDelete := cmdApi.GetCommand("delete")
+	_, err := cmdRoot.Resolve([]string{cmdRootName, "api", "get", "--host", "example"})
+	assert.Nil(t, err)
+
+	cmdList := &Command{Name: "list", Parent: cmdApi, Handler: testHandlerFunc}
+	cmdApi.Children = append(cmdApi.Children, cmdList)
+	optLimit := &Option{Name: "limit", Param: true, Parents: []*Command{cmdList}}
+	cmdList.Options = append(cmdList.Options, optLimit)
+	optHost := &Option{Name: "host", Param: true, Parents: []*Command{cmdList}}
+	cmdList.Options = append(cmdList.Options, optHost)
+
+	data, err := cmdRoot.Resolve([]string{cmdRootName, "api", "list", "--limit", "5", "--host", "example"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdList, data.Cmd)
+	assert.Equal(t, map[string]string{"limit": "5", "host": "example"}, data.Options)
+	assert.Equal(t, optHost, cmdList.GetOption("host", true))
+
+	// renaming directly, including to the alias of a sibling
+	cmdGet.Name = "read"
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("read"))
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("fetch"))
+	assert.Nil(t, cmdApi.GetCommand("get"))
+	cmdList.Name = "fetch"
+	assert.Equal(t, cmdList, cmdApi.GetCommand("fetch"))
+
+	// removing directly
+	cmdApi.Children = cmdApi.Children[:1]
+	assert.Equal(t, cmdGet, cmdApi.GetCommand("fetch"))
+	assert.Nil(t, cmdApi.GetCommand("delete"))
+
+	// options renamed directly to shadow the root
+	optForce := cmdDelete.GetOption("force", false)
+	assert.Equal(t, cmdRoot.GetOption("verbose", false), cmdDelete.GetOption("verbose", false))
+	optForce.Name = "verbose"
+	assert.Equal(t, optForce, cmdDelete.GetOption("verbose", false))
+	assert.Nil(t, cmdDelete.GetOption("force", false))
+	cmdDelete.Options = nil
+	assert.Equal(t, cmdRoot.GetOption("verbose", false), cmdDelete.GetOption("verbose", false))
+}
+
+// newWideTree creates a tree with siblings children beneath "api", each with a
+// leaf, and options global options on the root
+func newWideTree(siblings int, options int) *Command {
+	cmdRoot := newCommandRoot(nil)
+	for i := 0; i < options; i++ {
+		cmdRoot.NewOption(fmt.Sprintf("global%d", i), "global option", true)
+	}
+
+	cmdApi := cmdRoot.NewCommand("api", "api description", nil)
+	for i := 0; i < siblings; i++ {
+		cmdApi.NewCommand(fmt.Sprintf("resource%d", i), "resource", nil).NewCommand("get", "get", testHandlerFunc)
+	}
+
+	return cmdRoot
+}
+
+// BenchmarkResolve resolves the last sibling of increasingly wide trees, with an
+// option from the root, so the cost should remain flat
+func BenchmarkResolve(b *testing.B) {
+	for _, siblings := range []int{10, 100, 1000, 10000} {
+		cmdRoot := newWideTree(siblings, 100)
+		args := []string{cmdRootName, "api", fmt.Sprintf("resource%d", siblings-1), "get", "--global99", "value"}
+
+		b.Run(fmt.Sprintf("siblings=%d", siblings), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := cmdRoot.Resolve(args); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGetOption looks up the last of increasing numbers of root options from a
+// leaf, so the cost should remain flat
+func BenchmarkGetOption(b *testing.B) {
+	for _, options := range []int{10, 100, 1000} {
+		cmdGet := newWideTree(1, options).FindPath("api", "resource0", "get")
+		name := fmt.Sprintf("global%d", options-1)
+
+		b.Run(fmt.Sprintf("options=%d", options), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if cmdGet.GetOption(name, true) == nil {
+					b.Fatal("option not found")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"errors"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// mountEntry is a subtree registered via RegisterMount().
+type mountEntry struct {
+	// point Space separated path of the Command to mount beneath
+	point string
+	// cmd Subtree to mount, which is cloned for each tree
+	cmd *Command
+	// source Package which registered the subtree
+	source string
+}
+
+// mountRegistry holds the subtrees registered via RegisterMount(), in order.
+var mountRegistry struct {
+	sync.Mutex
+	entries []*mountEntry
+}
+
+// RegisterMount registers subtrees, built by another package, to be bound beneath the
+// Command at point when MountRegistered() is called on a tree.  point is the space
+// separated path of names from the root, or empty for the root itself.  This is
+// intended to be called from init() within packages publishing reusable subtrees, e.g.
+//   func init() {
+//     clicommand.RegisterMount("", authCommand())
+//   }
+//
+// The package calling RegisterMount is recorded as the source of each subtree, as
+// reported by GetSource().
+func RegisterMount(point string, cmdv ...*Command) {
+	source := callerPackage(2)
+
+	mountRegistry.Lock()
+	defer mountRegistry.Unlock()
+
+	for _, cmd := range cmdv {
+		mountRegistry.entries = append(mountRegistry.entries, &mountEntry{point, cmd, source})
+	}
+}
+
+// MountRegistered binds a copy of every subtree registered via RegisterMount() beneath
+// its mount point within this tree, in the order they were registered.  Each copy is
+// made via Clone(), so the registered subtrees may be mounted within several trees.
+//
+// Subtrees which cannot be mounted are skipped, with an ErrMountPoint or
+// ErrMountConflict error for each joined via errors.Join().
+func (c *Command) MountRegistered() error {
+	mountRegistry.Lock()
+	entries := append([]*mountEntry(nil), mountRegistry.entries...)
+	mountRegistry.Unlock()
+
+	var errs []error
+	for _, entry := range entries {
+		if err := c.mount(entry.point, entry.source, entry.cmd.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Mount binds subtrees beneath the Command at point within this tree, as
+// MountRegistered() does for registered subtrees, but immediately and without
+// copying them.  The package calling Mount is recorded as the source of each
+// subtree, as reported by GetSource().
+func (c *Command) Mount(point string, cmdv ...*Command) error {
+	return c.mount(point, callerPackage(2), cmdv...)
+}
+
+// GetSource returns the package which mounted this Command, or the nearest parent
+// which was mounted, via Mount() or RegisterMount().  An empty string is returned
+// for commands which are not within a mounted subtree.
+func (c *Command) GetSource() string {
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		if cmd.source != "" {
+			return cmd.source
+		}
+	}
+
+	return ""
+}
+
+// mount binds subtrees beneath the Command at point, recording source on each.
+func (c *Command) mount(point string, source string, cmdv ...*Command) error {
+	parent := c.FindPath(strings.Fields(point)...)
+	if parent == nil || parent.Handler != nil {
+		return &ErrMountPoint{c, point}
+	}
+
+	var errs []error
+	for _, cmd := range cmdv {
+		if existing := parent.mountConflict(cmd); existing != nil {
+			errs = append(errs, &ErrMountConflict{parent, cmd, source, existing})
+			continue
+		}
+
+		if cmd.source == "" {
+			cmd.source = source
+		}
+		parent.BindCommand(cmd)
+	}
+
+	return errors.Join(errs...)
+}
+
+// mountConflict returns the child of this Command sharing a name or alias with cmd,
+// or nil.
+func (c *Command) mountConflict(cmd *Command) *Command {
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if existing := c.GetCommand(name); existing != nil {
+			return existing
+		}
+	}
+
+	return nil
+}
+
+// callerPackage returns the import path of the package of the function skip frames
+// above the caller of callerPackage, or an empty string if it cannot be found.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	return funcPackage(fn.Name())
+}
+
+// funcPackage returns the import path of the package from a function name, as
+// reported by runtime.FuncForPC.  Function names are the package path, then a dot,
+// then the function, with dots and other special characters in the last element of
+// the path escaped as "%xx", e.g. "gopkg.in/yaml%2ev3.Unmarshal".
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		name = name[:slash+1+dot]
+	}
+
+	if path, err := url.PathUnescape(name); err == nil {
+		return path
+	}
+
+	return name
+}
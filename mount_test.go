@@ -0,0 +1,84 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPackage the package tests are within, recorded as the mount source.  This is
+// taken from a type within the package, so is correct whatever path the package is
+// built under.
+var testPackage = reflect.TypeOf(Command{}).PkgPath()
+
+// newAuthTree creates a subtree as a package publishing it would
+func newAuthTree() *Command {
+	cmdAuth := NewCommand("auth", "auth description", nil).SetAliases("login")
+	cmdAuth.NewCommand("token", "token description", testHandlerFunc)
+
+	return cmdAuth
+}
+
+// Mount testing, validate subtrees are bound with their source recorded
+func TestMount(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdAuth := newAuthTree()
+
+	assert.Nil(t, cmdRoot.Mount("api", cmdAuth))
+	assert.Equal(t, cmdAuth, cmdRoot.FindPath("api", "auth"))
+	assert.Equal(t, testPackage, cmdAuth.GetSource())
+	assert.Equal(t, testPackage, cmdRoot.FindPath("api", "auth", "token").GetSource())
+	assert.Equal(t, "", cmdRoot.FindPath("api").GetSource())
+
+	var conflict *ErrMountConflict
+	err := cmdRoot.Mount("api", NewCommand("LOGIN", "login description", testHandlerFunc))
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, cmdAuth, conflict.Existing)
+		assert.Equal(t, "Cannot mount 'LOGIN' from "+testPackage+" beneath 'root api': conflicts with 'auth' from "+testPackage, err.Error())
+	}
+
+	err = cmdRoot.Mount("api get", newAuthTree())
+	assert.EqualError(t, err, "Mount point not found: root api get")
+	err = cmdRoot.Mount("missing", newAuthTree())
+	assert.EqualError(t, err, "Mount point not found: root missing")
+}
+
+// MountRegistered testing, validate registered subtrees are cloned into each tree
+func TestMountRegistered(t *testing.T) {
+	saved := mountRegistry.entries
+	defer func() {
+		mountRegistry.entries = saved
+	}()
+
+	cmdAuth := newAuthTree()
+	RegisterMount("", cmdAuth)
+	RegisterMount("api", NewCommand("get", "conflicting get", testHandlerFunc))
+
+	cmdRoot := newSpecTree()
+	err := cmdRoot.MountRegistered()
+	assert.EqualError(t, err, "Cannot mount 'get' from "+testPackage+" beneath 'root api': conflicts with 'get' from the tree")
+
+	mounted := cmdRoot.GetCommand("auth")
+	if assert.NotNil(t, mounted) {
+		assert.NotSame(t, cmdAuth, mounted)
+		assert.Nil(t, cmdAuth.Parent)
+		assert.Equal(t, testPackage, mounted.GetSource())
+	}
+
+	other := newSpecTree()
+	other.MountRegistered()
+	assert.NotNil(t, other.GetCommand("auth"))
+}
+
+// callerPackage testing, validate package paths are extracted from function names
+func TestCallerPackage(t *testing.T) {
+	assert.Equal(t, testPackage, callerPackage(1))
+	assert.Equal(t, "gopkg.in/yaml.v3", funcPackage("gopkg.in/yaml%2ev3.Unmarshal"))
+	assert.Equal(t, "example.com", funcPackage("example%2ecom.(*Type).Method"))
+	assert.Equal(t, "example.com/pkg", funcPackage("example.com/pkg.init.func1"))
+}
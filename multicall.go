@@ -0,0 +1,47 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SetMultiCall sets whether the program name selects the Command parsing starts
+// from, allowing a single binary to be installed under several names as busybox
+// is.  This should only be called on the root Command.
+//
+// When set, if the base name of args[0] matches the name or an alias of a Command
+// anywhere within the tree, parsing starts from that Command rather than the root,
+// and help shows the name the program was invoked as, e.g. with a symlink
+// "get" pointing at the binary:
+//   ./get --id 1  =>  ./root api get --id 1
+//
+// The shallowest match wins, and a name matching the root is parsed as normal.
+func (c *Command) SetMultiCall(multicall bool) *Command {
+	c.MultiCall = multicall
+	return c
+}
+
+// multiCall returns the Command selected by the program name, and the name it was
+// invoked as, or nil if the program name does not select a Command.
+func (c *Command) multiCall(argv0 string) (*Command, string) {
+	name := strings.TrimSuffix(filepath.Base(argv0), ".exe")
+	if name == "" || strings.EqualFold(name, c.Name) {
+		return nil, ""
+	}
+
+	for level := []*Command{c}; len(level) > 0; {
+		var next []*Command
+		for _, cmd := range level {
+			if match := cmd.GetCommand(name); match != nil {
+				return match, name
+			}
+			next = append(next, cmd.Children...)
+		}
+		level = next
+	}
+
+	return nil, ""
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newMultiCallTree creates the help tree with a deeper parent, and MultiCall set
+func newMultiCallTree() *Command {
+	cmdRoot, _ := newHelpTree()
+	cmdRoot.SetMultiCall(true)
+	cmdRoot.NewCommand("api", "api description", nil).NewCommand("get", "get description", testHandlerFunc).SetAliases("fetch")
+
+	return cmdRoot
+}
+
+// MultiCall testing, validate the program name selects where parsing starts
+func TestMultiCall(t *testing.T) {
+	cmdRoot := newMultiCallTree()
+	cmdGet := cmdRoot.FindPath("api", "get")
+
+	data, err := cmdRoot.Resolve([]string{"/usr/bin/get", "-option", "param"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdGet, data.Cmd)
+	assert.Equal(t, map[string]string{optionName: ""}, data.Options)
+	assert.Equal(t, []string{"param"}, data.Params)
+
+	data, err = cmdRoot.Resolve([]string{"FETCH.exe"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdGet, data.Cmd)
+
+	data, err = cmdRoot.Resolve([]string{"api", "get"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdGet, data.Cmd)
+
+	data, err = cmdRoot.Resolve([]string{"api"})
+	assert.Nil(t, err)
+	assert.True(t, data.IsHelp())
+
+	data, err = cmdRoot.Resolve([]string{"/usr/bin/root", "api", "get"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdGet, data.Cmd)
+
+	cmdRoot.SetMultiCall(false)
+	data, err = cmdRoot.Resolve([]string{"get", "api", "get"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdGet, data.Cmd)
+}
+
+// MultiCall testing, validate help shows the invoked name
+func TestMultiCallHelp(t *testing.T) {
+	cmdRoot := newMultiCallTree()
+	out := cmdRoot.Stdout.(interface {
+		String() string
+		Reset()
+	})
+
+	cmdRoot.ParseContext(context.Background(), []string{"fetch", "help"})
+	assert.Equal(t, `
+fetch - get description
+fetch [-option]
+Aliases: fetch
+
+fetch options:
+   -option option description
+
+`, out.String())
+
+	out.Reset()
+	cmdRoot.ParseContext(context.Background(), []string{"api"})
+	assert.Equal(t, `
+api - api description
+api [-option]
+
+api options:
+   -option option description
+
+Available subcommands:
+  get get description
+
+For help information run:
+  'api help' .. 'api <commands>* help' .. 'api help -k <keyword>'
+
+`, out.String())
+
+	out.Reset()
+	cmdRoot.ParseContext(context.Background(), []string{"api", "bogus"})
+	assert.Contains(t, out.String(), "For help information, run: api help\n")
+}
+
+// MultiCall testing, validate options of the invoked Command and its parents are
+// listed together, and deprecated parents which were not given are not warned about
+func TestMultiCallParents(t *testing.T) {
+	cmdRoot := newMultiCallTree()
+	out := cmdRoot.Stdout.(interface{ String() string })
+	cmdApi := cmdRoot.GetCommand("api").SetDeprecated("", "")
+	cmdApi.NewOption("host", "host description", true)
+	cmdApi.GetCommand("get").NewOption("id", "id description", true)
+
+	cmdRoot.ParseContext(context.Background(), []string{"get", "help"})
+	assert.Equal(t, `
+get - get description
+get [-option] [--host <host>] [--id <id>]
+Aliases: fetch
+
+get options:
+   -option     option description
+  --host <arg> host description
+  --id <arg>   id description
+
+`, out.String())
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"reflect"
+)
+
+// UnbindCommand unbinds a series of subcommands, so they are no longer children.
+// Links are removed in both directions, but each subcommand keeps its own options
+// and children, so it can be bound elsewhere via BindCommand() or Move().
+func (c *Command) UnbindCommand(cmdv ...*Command) {
+	for _, cmd := range cmdv {
+		var newchildren []*Command
+		for _, child := range c.Children {
+			if child != cmd {
+				newchildren = append(newchildren, child)
+			}
+		}
+
+		if len(newchildren) != len(c.Children) {
+			c.Children = newchildren
+			cmd.Parent = nil
+
+			// the subtree now has cmd as its root, and may have changed since its
+			// index was last built beneath it
+			cmd.invalidateLookup()
+		}
+	}
+
+	c.invalidateLookup()
+}
+
+// RemoveCommand unbinds a series of subcommands as UnbindCommand() does, and
+// additionally unbinds every Option from each subcommand and all of its children.
+// Options shared with the rest of the tree are then no longer linked to the removed
+// commands.  This is intended for pruning commands from a tree which are not wanted,
+// e.g. debugging commands within a release build.
+func (c *Command) RemoveCommand(cmdv ...*Command) {
+	c.UnbindCommand(cmdv...)
+
+	for _, cmd := range cmdv {
+		cmd.Walk(func(sub *Command, depth int) error {
+			sub.UnbindOption(sub.Options...)
+			return nil
+		})
+	}
+}
+
+// Move unbinds this Command from its parent, if any, and binds it as a child of
+// parent along with all of its options and children.  Options inherited from the
+// old parent are no longer available, so Validate() should be used to check any
+// required options or option groups still refer to available options.
+//
+// If parent has a handler set, or is this Command or one of its children, this
+// will panic.
+func (c *Command) Move(parent *Command) {
+	for cmd := parent; cmd != nil; cmd = cmd.Parent {
+		if cmd == c {
+			panic("Move() Parent is within the command being moved: " + c.GetNameChain())
+		}
+	}
+
+	parent.BindCommand(c)
+}
+
+// Clone returns a deep copy of this Command and all of its children, unbound from
+// any parent.  Options bound within the tree are copied, with an Option shared by
+// several commands remaining shared between the copies.  Required options and
+// option groups referring to Options bound outside of the tree continue to refer to
+// the original Options.
+//
+// Handlers, callbacks and writers are shared with the original, but all other
+// changes made to the copy do not affect the original, making this useful for
+// giving each test its own tree.
+func (c *Command) Clone() *Command {
+	options := make(map[*Option]*Option)
+	clone := c.cloneRecurse(nil, options)
+
+	clone.Walk(func(cmd *Command, depth int) error {
+		var required []*Option
+		for _, option := range cmd.RequiredOptions {
+			required = append(required, cloneOptionRef(options, option))
+		}
+		cmd.RequiredOptions = required
+
+		var groups []*OptionGroup
+		for _, group := range cmd.OptionGroups {
+			groupclone := &OptionGroup{Type: group.Type}
+			for _, option := range group.Options {
+				groupclone.Options = append(groupclone.Options, cloneOptionRef(options, option))
+			}
+			groups = append(groups, groupclone)
+		}
+		cmd.OptionGroups = groups
+
+		return nil
+	})
+
+	return clone
+}
+
+// cloneRecurse copies this Command and its children beneath parent, recording each
+// Option copied within options.
+func (c *Command) cloneRecurse(parent *Command, options map[*Option]*Option) *Command {
+	clone := *c
+	clone.Parent = parent
+	if c.lookup != nil {
+		clone.lookup = &lookupIndex{}
+	}
+	clone.Aliases = append([]string(nil), c.Aliases...)
+	clone.Callbackspre = append([]Handler(nil), c.Callbackspre...)
+	clone.Callbacks = append([]Handler(nil), c.Callbacks...)
+	clone.Deprecated = cloneDeprecation(c.Deprecated)
+
+	clone.Examples = nil
+	for _, example := range c.Examples {
+		exampleclone := *example
+		clone.Examples = append(clone.Examples, &exampleclone)
+	}
+
+	clone.Topics = nil
+	for _, topic := range c.Topics {
+		topicclone := *topic
+		clone.Topics = append(clone.Topics, &topicclone)
+	}
+
+	if c.Palette != nil {
+		palette := *c.Palette
+		clone.Palette = &palette
+	}
+
+	if c.errorVerbosity != nil {
+		clone.errorVerbosity = make(map[reflect.Type]ErrorVerbosity)
+		for errtype, verbosity := range c.errorVerbosity {
+			clone.errorVerbosity[errtype] = verbosity
+		}
+	}
+
+	clone.Options = nil
+	for _, option := range c.Options {
+		optionclone, ok := options[option]
+		if !ok {
+			copied := *option
+			copied.Parents = nil
+			copied.Deprecated = cloneDeprecation(option.Deprecated)
+			optionclone = &copied
+			options[option] = optionclone
+		}
+
+		optionclone.BindCommand(&clone)
+	}
+
+	clone.Children = nil
+	for _, child := range c.Children {
+		clone.Children = append(clone.Children, child.cloneRecurse(&clone, options))
+	}
+
+	return &clone
+}
+
+// cloneOptionRef returns the copy of option, or option itself if it was not copied.
+func cloneOptionRef(options map[*Option]*Option, option *Option) *Option {
+	if optionclone, ok := options[option]; ok {
+		return optionclone
+	}
+
+	return option
+}
+
+// cloneDeprecation returns a copy of deprecation, or nil.
+func cloneDeprecation(deprecation *Deprecation) *Deprecation {
+	if deprecation == nil {
+		return nil
+	}
+
+	copied := *deprecation
+	return &copied
+}
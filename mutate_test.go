@@ -0,0 +1,110 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// UnbindCommand testing, validate links are removed in both directions
+func TestUnbindCommand(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdGet := cmdApi.GetCommand("get")
+
+	cmdApi.UnbindCommand(cmdGet)
+	assert.Nil(t, cmdGet.Parent)
+	assert.Nil(t, cmdApi.GetCommand("get"))
+	assert.Len(t, cmdApi.Children, 1)
+
+	// unbinding a command which is not a child is ignored
+	cmdRoot.UnbindCommand(cmdApi.GetCommand("delete"))
+	assert.Equal(t, cmdApi, cmdApi.GetCommand("delete").Parent)
+}
+
+// RemoveCommand testing, validate shared options no longer link to removed commands
+func TestRemoveCommand(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdDelete := cmdApi.GetCommand("delete")
+	optShared := NewOption("shared", "shared option", false)
+	optShared.BindCommand(cmdRoot)
+	optShared.BindCommand(cmdDelete)
+
+	cmdRoot.RemoveCommand(cmdApi)
+	assert.Nil(t, cmdApi.Parent)
+	assert.Nil(t, cmdRoot.GetCommand("api"))
+	assert.Equal(t, []*Command{cmdRoot}, optShared.Parents)
+	assert.Empty(t, cmdDelete.Options)
+	assert.Empty(t, cmdApi.Options)
+}
+
+// Move testing, validate commands are re-parented and cycles are refused
+func TestMove(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdGet := cmdApi.GetCommand("get")
+
+	cmdGet.Move(cmdRoot)
+	assert.Equal(t, cmdRoot, cmdGet.Parent)
+	assert.Equal(t, cmdGet, cmdRoot.GetCommand("fetch"))
+	assert.Nil(t, cmdApi.GetCommand("get"))
+	assert.Equal(t, "root get", cmdGet.GetNameChain())
+
+	assert.Panics(t, func() { cmdRoot.Move(cmdApi) })
+	assert.Panics(t, func() { cmdApi.Move(cmdApi) })
+	assert.Panics(t, func() { cmdApi.Move(cmdGet) })
+}
+
+// Clone testing, validate the copy is independent of the original
+func TestClone(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	optShared := NewOption("shared", "shared option", false)
+	optShared.BindCommand(cmdApi)
+	optShared.BindCommand(cmdApi.GetCommand("get"))
+	cmdApi.SetOptionRequired(cmdRoot.GetOption("verbose", false))
+	cmdApi.NewOptionGroup(OptionGroupExclusive, optShared, cmdApi.GetOption("host", true))
+	example := cmdApi.NewExample("root api get", "get example")
+
+	clone := cmdRoot.Clone()
+	assert.Equal(t, cmdRoot.GetSpec(), clone.GetSpec())
+	assert.Nil(t, clone.Parent)
+	assert.Nil(t, clone.Validate())
+
+	apiClone := clone.GetCommand("api")
+	assert.NotSame(t, cmdApi, apiClone)
+	assert.Equal(t, clone, apiClone.Parent)
+
+	sharedClone := apiClone.GetOption("shared", false)
+	assert.NotSame(t, optShared, sharedClone)
+	assert.Same(t, sharedClone, apiClone.GetCommand("get").GetOption("shared", false))
+	assert.Equal(t, []*Command{apiClone, apiClone.GetCommand("get")}, sharedClone.Parents)
+	assert.Same(t, clone.GetOption("verbose", false), apiClone.RequiredOptions[0])
+	assert.Same(t, sharedClone, apiClone.OptionGroups[0].Options[0])
+
+	apiClone.Name = "changed"
+	sharedClone.SetRequired()
+	apiClone.Examples[0].Desc = "changed"
+	assert.Equal(t, "api", cmdApi.Name)
+	assert.False(t, optShared.Required)
+	assert.Equal(t, "get example", example.Desc)
+
+	// cloning a subtree keeps references to options bound outside of it
+	apiOnly := cmdApi.Clone()
+	assert.Same(t, cmdRoot.GetOption("verbose", false), apiOnly.RequiredOptions[0])
+}
+
+// BindCommand testing, validate binding a bound command moves it
+func TestBindCommandRebind(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdGet := cmdApi.GetCommand("get")
+
+	cmdRoot.BindCommand(cmdGet)
+	assert.Nil(t, cmdApi.GetCommand("get"))
+	assert.Equal(t, cmdRoot, cmdGet.Parent)
+}
@@ -21,6 +21,9 @@ type Option struct {
 	// desc Description of option
 	Desc string
 
+	// descKey Key of the translation of Desc within the Catalog, or empty
+	DescKey string
+
 	// param Controls whether this option takes parameters or not.
 	//
 	// For simplicity, all options that take parameters have a double dash
@@ -35,6 +38,15 @@ type Option struct {
 	// detect it is not supplied and return an error.
 	Required bool
 
+	// hidden Controls whether this option is omitted from help output.  Hidden
+	// options are still parsed as normal.
+	Hidden bool
+
+	// deprecated Deprecation details, or nil if the option is not deprecated.
+	// Deprecated options are still parsed, but a warning is written whenever
+	// they are used.
+	Deprecated *Deprecation
+
 	// parents Array of pointers which this Option is bound to
 	Parents []*Command
 }
@@ -60,6 +72,7 @@ func NewOption(name string, desc string, param bool) *Option {
 func (o *Option) BindCommand(cmd *Command) {
 	o.Parents = append(o.Parents, cmd)
 	cmd.Options = append(cmd.Options, o)
+	cmd.invalidateLookup()
 }
 
 // UnbindCommand unbinds an Option from the given Command object, at which
@@ -82,23 +95,54 @@ func (o *Option) UnbindCommand(cmd *Command) {
 
 	o.Parents = newparents
 	cmd.Options = newoptions
+	cmd.invalidateLookup()
 }
 
 // GetRequired returns whether this Option must be specified.  This requirement
 // only applies to Options that are directly on the path between the edge Command
-// and the root.
+// and the root.  Requirements set for specific commands via
+// Command.SetOptionRequired() are reported by Command.GetOptionRequired().
 func (o *Option) GetRequired() bool {
 	return o.Required
 }
 
+// SetName renames the Option, rebuilding the lookup index on next use.
+func (o *Option) SetName(name string) *Option {
+	o.Name = name
+	for _, cmd := range o.Parents {
+		cmd.invalidateLookup()
+	}
+	return o
+}
+
 // SetRequired marks the Option so it must be specified.  This requirement only
 // applies to Options that are directly on the path between the edge Command
-// and the root.
+// and the root, wherever the Option is bound.  To require a shared Option for
+// only some commands, use Command.SetOptionRequired().
 func (o *Option) SetRequired() *Option {
 	o.Required = true
 	return o
 }
 
+// SetHidden marks the Option as hidden, so it is omitted from help output but is
+// otherwise parsed as normal.
+func (o *Option) SetHidden() *Option {
+	o.Hidden = true
+	return o
+}
+
+// SetDeprecated marks the Option as deprecated.  It continues to be parsed as
+// normal, but a warning is written to the error writer whenever it is used.
+// message and replacement are both optional, with replacement naming the option
+// the user should use instead.
+func (o *Option) SetDeprecated(message string, replacement string) *Option {
+	o.Deprecated = &Deprecation{
+		Message:     message,
+		Replacement: replacement,
+	}
+	return o
+}
+
 // GetParents returns the parents Command objects of an Option
 func (o *Option) GetParents() []*Command {
 	return o.Parents
@@ -0,0 +1,161 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"strings"
+)
+
+// OptionGroupType defines the relationship between the Options of an OptionGroup.
+type OptionGroupType int
+
+const (
+	// OptionGroupExclusive At most one of the Options may be supplied.
+	OptionGroupExclusive OptionGroupType = iota
+	// OptionGroupRequires If the first Option is supplied, all of the remaining
+	// Options must also be supplied.
+	OptionGroupRequires
+	// OptionGroupAtLeastOne At least one of the Options must be supplied.
+	OptionGroupAtLeastOne
+	// OptionGroupExactlyOne Exactly one of the Options must be supplied.
+	OptionGroupExactlyOne
+)
+
+// An OptionGroup represents a relationship between several Options, which is
+// checked by the parser alongside required options.  Groups are bound to a Command
+// and like Options, apply to that Command and all of its children.
+//
+// The Options within a group do not need to be bound to the same Command as the
+// group, providing they are available to it, e.g.
+//   cliRoot.NewOptionGroup(clicommand.OptionGroupExclusive, optUpper, optLower)
+type OptionGroup struct {
+	// Type Relationship between the Options
+	Type OptionGroupType
+	// Options Options within the group.  For OptionGroupRequires the first Option
+	// is the one requiring the others.
+	Options []*Option
+}
+
+// NewOptionGroup creates a new OptionGroup of the given type and automatically binds
+// it to the Command.
+//
+// OptionGroupRequires groups must have at least two Options, all other types must
+// have at least one, otherwise this will panic.
+func (c *Command) NewOptionGroup(grouptype OptionGroupType, optionv ...*Option) *OptionGroup {
+	if len(optionv) == 0 || grouptype == OptionGroupRequires && len(optionv) < 2 {
+		panic("NewOptionGroup() Not enough options for group: " + c.GetNameChain())
+	}
+
+	group := &OptionGroup{
+		Type:    grouptype,
+		Options: optionv,
+	}
+	c.OptionGroups = append(c.OptionGroups, group)
+
+	return group
+}
+
+// supplied returns the Options within the group that were supplied.
+func (g *OptionGroup) supplied(data *Data) []*Option {
+	var options []*Option
+	for _, option := range g.Options {
+		if _, ok := data.Options[option.Name]; ok {
+			options = append(options, option)
+		}
+	}
+
+	return options
+}
+
+// check validates the group against the supplied options, returning the relevant
+// error if it is not satisfied.
+func (g *OptionGroup) check(cmd *Command, data *Data) error {
+	supplied := g.supplied(data)
+
+	switch g.Type {
+	case OptionGroupExclusive:
+		if len(supplied) > 1 {
+			return &ErrOptionConflict{cmd, g, supplied}
+		}
+	case OptionGroupRequires:
+		if _, ok := data.Options[g.Options[0].Name]; !ok {
+			return nil
+		}
+		for _, option := range g.Options[1:] {
+			if _, ok := data.Options[option.Name]; !ok {
+				return &ErrOptionRequires{cmd, g, g.Options[0], option}
+			}
+		}
+	case OptionGroupAtLeastOne:
+		if len(supplied) == 0 {
+			return &ErrOptionGroupMissing{cmd, g}
+		}
+	case OptionGroupExactlyOne:
+		if len(supplied) == 0 {
+			return &ErrOptionGroupMissing{cmd, g}
+		} else if len(supplied) > 1 {
+			return &ErrOptionConflict{cmd, g, supplied}
+		}
+	}
+
+	return nil
+}
+
+// String describes the relationship, in the form used within help output.
+func (g *OptionGroup) String() string {
+	return g.describe(nil)
+}
+
+// describe describes the relationship, translated for the tree cmd is within.  cmd
+// may be nil.
+func (g *OptionGroup) describe(cmd *Command) string {
+	switch g.Type {
+	case OptionGroupExclusive:
+		return message(cmd, MsgRuleExclusive, optionFlagList(g.Options))
+	case OptionGroupRequires:
+		return message(cmd, MsgRuleRequires, optionFlag(g.Options[0]), optionFlagList(g.Options[1:]))
+	case OptionGroupAtLeastOne:
+		return message(cmd, MsgRuleAtLeastOne, optionFlagList(g.Options))
+	case OptionGroupExactlyOne:
+		return message(cmd, MsgRuleExactlyOne, optionFlagList(g.Options))
+	}
+
+	return optionFlagList(g.Options)
+}
+
+// hasOptionGroups iterates over all OptionGroup entries in the tree validating each
+// is satisfied.  It starts at the leaf and moves up towards the root.
+func (c *Command) hasOptionGroups(data *Data) error {
+	for _, group := range c.OptionGroups {
+		if err := group.check(data.Cmd, data); err != nil {
+			return err
+		}
+	}
+
+	if c.Parent != nil {
+		return c.Parent.hasOptionGroups(data)
+	}
+
+	return nil
+}
+
+// optionFlag returns the Option name as it is given on the command line.
+func optionFlag(option *Option) string {
+	if option.Param {
+		return "--" + option.Name
+	}
+
+	return "-" + option.Name
+}
+
+// optionFlagList returns a comma separated list of Option names as they are given
+// on the command line.
+func optionFlagList(optionv []*Option) string {
+	var flags []string
+	for _, option := range optionv {
+		flags = append(flags, optionFlag(option))
+	}
+
+	return strings.Join(flags, ", ")
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"sort"
+	"strings"
+)
+
+// CommandOrder defines the order child commands are listed in.
+type CommandOrder int
+
+const (
+	// OrderInsertion Children are listed in the order they were bound.
+	OrderInsertion CommandOrder = iota
+	// OrderAlphabetical Children are listed alphabetically by name, ignoring case.
+	OrderAlphabetical
+)
+
+// SetGroup sets the name of the group the Command is listed under, within the
+// help output of its parent.  Commands without a group are listed first, under
+// "Available subcommands".
+func (c *Command) SetGroup(group string) *Command {
+	c.Group = group
+	return c
+}
+
+// SetCommandOrder sets the order child commands are listed in for the whole tree.
+// This should only be called on the root Command.
+func (c *Command) SetCommandOrder(order CommandOrder) *Command {
+	c.CommandOrder = order
+	return c
+}
+
+// GetChildren returns the children of the Command in the order configured on the
+// root Command via SetCommandOrder().  Anything listing commands should use this,
+// so listings are ordered consistently.
+func (c *Command) GetChildren() []*Command {
+	children := append([]*Command(nil), c.Children...)
+
+	if c.GetRoot().CommandOrder == OrderAlphabetical {
+		sort.SliceStable(children, func(i, j int) bool {
+			return strings.ToLower(children[i].Name) < strings.ToLower(children[j].Name)
+		})
+	}
+
+	return children
+}
+
+// GetGroups returns the names of the groups used by the children of the Command.
+// The empty group for ungrouped children is always first if used, with the
+// remaining groups in the order they are first used by a child, regardless of the
+// configured CommandOrder.
+func (c *Command) GetGroups() []string {
+	var groups []string
+	seen := make(map[string]bool)
+
+	for _, child := range c.Children {
+		if child.Group == "" && !seen[""] {
+			groups = append([]string{""}, groups...)
+		} else if !seen[child.Group] {
+			groups = append(groups, child.Group)
+		}
+		seen[child.Group] = true
+	}
+
+	return groups
+}
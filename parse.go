@@ -1,12 +1,24 @@
 package clicommand
 
 import (
+	"context"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/leehuk/go-clicommand/internal/hook"
 )
 
+// init gives clicommandtest the Data passed to the Handler via the hook package.
+func init() {
+	hook.ParseContext = func(ctx context.Context, root interface{}, args []string) (interface{}, error) {
+		return root.(*Command).parseContext(ctx, args)
+	}
+}
+
 // Parse parses the command line from os.Args under the supplied command tree, then
-// acts accordingly based on the results.
+// acts accordingly based on the results.  It is equivalent to calling ParseContext
+// with context.Background() and os.Args.
 //
 // Everything specified on the command line is either a subcommand, option or a
 // generic parameter.
@@ -18,18 +30,148 @@ import (
 //
 // The parsing will steal the arg "help" if it detects it as the first unknown
 // parameter, allowing for easy access to the available commands and options.
+// Similarly the option "-no-color" is stolen to disable coloured output, unless
+// the tree defines an option of that name.
 //
 // If parsing is not ok, it will return one of several internal error types.
 func (c *Command) Parse() error {
+	return c.ParseContext(context.Background(), os.Args)
+}
+
+// optionNoColor is the built in option disabling colour in help and errors.
+const optionNoColor = "-no-color"
+
+// ParseContext parses the supplied command line under the command tree, exactly as
+// Parse does, but using args in place of os.Args and passing ctx through to all
+// callbacks and the Handler via Data.Ctx.  args[0] is the program name, and is
+// skipped.
+//
+// If MultiCall is set on the root Command, the base name of args[0] may select the
+// Command parsing starts from, see SetMultiCall().
+//
+// If Strict is set on the root Command, the tree is first checked via Validate(),
+// returning an ErrTreeInvalid error without parsing if any Problem is found.
+//
+// If SignalCancel is set on the root Command, the context is additionally cancelled
+// when the program receives SIGINT or SIGTERM.  Once the context has been cancelled,
+// for any reason, ParseContext returns an ErrCancelled error in place of running the
+// Handler, or in place of any error the Handler returns.
+func (c *Command) ParseContext(ctx context.Context, args []string) error {
+	_, err := c.parseContext(ctx, args)
+	return err
+}
+
+// parseContext implements ParseContext, additionally returning the Data passed to
+// the callbacks and Handler, or holding the position reached within the tree.
+func (c *Command) parseContext(ctx context.Context, args []string) (*Data, error) {
+	if c.SignalCancel {
+		var stop func()
+		ctx, stop = signalContext(ctx)
+		defer stop()
+	}
+
+	if err := c.validateStrict(); err != nil {
+		return &Data{Ctx: ctx, Cmd: c, Options: make(map[string]string)}, err
+	}
+
+	result, err := c.resolveChecked(ctx, args)
+	commandData := result.data
+	commandPtr := commandData.Cmd
+
+	result.warnDeprecated(c.GetRoot().getStderr())
+
+	if _, ok := err.(*ErrOptionMissingParam); ok {
+		return commandData, err
+	} else if err != nil {
+		return commandData, helpError(commandData, err)
+	}
+
+	handler := helpUsage
+	if !commandData.help {
+		if e := commandPtr.runCallbacks(commandData); e != nil {
+			return commandData, helpError(commandData, &ErrCallback{commandPtr, e})
+		}
+
+		handler = commandPtr.Handler
+	} else if !result.help {
+		// no subcommand specified at the root level, or the level selected by the
+		// program name
+		helpUsage(commandData)
+		return commandData, nil
+	}
+
+	if ctx.Err() != nil {
+		return commandData, newErrCancelled(ctx, commandPtr)
+	}
+
+	if e := handler(commandData); e != nil {
+		if ctx.Err() != nil {
+			return commandData, newErrCancelled(ctx, commandPtr)
+		} else if _, ok := e.(*ErrHelpKeyword); ok {
+			return commandData, helpError(commandData, e)
+		}
+		return commandData, &ErrCommandError{commandPtr, e}
+	}
+
+	return commandData, nil
+}
+
+// Resolve parses args under the command tree exactly as ParseContext does, returning
+// the Data the Handler would be called with, but without running the validation
+// callbacks or the Handler and without writing any output.  Pre-validation
+// callbacks are run, as they may supply options, then required options and option
+// groups are checked as normal.
+//
+// Data is returned even when an error is, holding the position reached within the
+// tree.  If help was requested, Data.Cmd is the Command help would be displayed for
+// and Data.IsHelp() is true.
+func (c *Command) Resolve(args []string) (*Data, error) {
+	if err := c.validateStrict(); err != nil {
+		return &Data{Ctx: context.Background(), Cmd: c, Options: make(map[string]string)}, err
+	}
+
+	result, err := c.resolveChecked(context.Background(), args)
+	return result.data, err
+}
+
+// parseResult holds the outcome of resolving a command line under the tree.
+type parseResult struct {
+	// data Data for the selected Command, without a context
+	data *Data
+	// help Whether the help command was given
+	help bool
+	// path Command objects selected on the command line, from the root
+	path []*Command
+	// options Option objects given on the command line, in order
+	options []*Option
+}
+
+// resolve walks args under the command tree, finding the selected Command, the
+// options and the parameters, without running any callbacks or handlers.  If an
+// error is found, the parseResult holds the position reached so far.
+func (c *Command) resolve(args []string) (*parseResult, error) {
 	var commandPtr = c
-	var commandData = &Data{
-		Cmd:     c,
-		Options: make(map[string]string),
+	var result = &parseResult{
+		data: &Data{
+			Cmd:     c,
+			Options: make(map[string]string),
+		},
+	}
+	var commandData = result.data
+
+	if c.GetRoot().MultiCall && len(args) > 0 {
+		if cmd, name := c.multiCall(args[0]); cmd != nil {
+			commandPtr = cmd
+			commandData.Cmd = cmd
+			commandData.invoked = cmd
+			commandData.invokedName = name
+			result.path = []*Command{cmd}
+		}
 	}
 
 	var paramParsing = false
-	for i := 1; i < len(os.Args); i++ {
-		arg := os.Args[i]
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
 
 		if len(arg) >= 1 && arg[:1] == "-" {
 			// option argument
@@ -39,19 +181,19 @@ func (c *Command) Parse() error {
 
 			// ensure we do not have an option with no name
 			if len(arg) == 1 && arg[:1] == "-" || len(arg) == 2 && arg[:2] == "--" {
-				return helpError(commandData, &ErrOptionUnknown{arg})
+				return result, &ErrOptionUnknown{commandPtr, arg}
 			}
 
 			if arg[:2] == "--" {
 				// option with parameter: "--xyz"
 
 				// ensure we have a parameter
-				if i+1 >= len(os.Args) {
-					return &ErrOptionMissingParam{arg}
+				if i+1 >= len(args) {
+					return result, &ErrOptionMissingParam{commandPtr, arg}
 				}
 
 				optionname = arg[2:]
-				optionval = os.Args[i+1]
+				optionval = args[i+1]
 				optionparam = true
 
 				// next arg was an option to this param, skip its parsing
@@ -65,72 +207,95 @@ func (c *Command) Parse() error {
 			}
 
 			if subarg := commandPtr.GetOption(optionname, optionparam); subarg != nil {
-				commandData.Options[optionname] = optionval
+				result.options = append(result.options, subarg)
+				commandData.Options[subarg.Name] = optionval
+			} else if arg == optionNoColor {
+				// built in option, unless the tree defines its own
+				commandData.noColor = true
 			} else {
-				return helpError(commandData, &ErrOptionUnknown{arg})
+				return result, &ErrOptionUnknown{commandPtr, arg}
 			}
 		} else if paramParsing {
 			// parameter parsing
-			commandData.Params = append(commandData.Params, os.Args[i])
+			commandData.Params = append(commandData.Params, args[i])
 		} else if subcmd := commandPtr.GetCommand(arg); subcmd != nil {
 			// sub-menu
 
 			// repoint our pointer to this sub-menu and continue parsing
 			commandPtr = subcmd
 			commandData.Cmd = commandPtr
+			result.path = append(result.path, commandPtr)
 		} else if strings.EqualFold(arg, "help") {
 			// help command as sub-menu
 
-			// take any remaining fields as parameters
-			if len(os.Args) >= i {
-				commandData.Params = os.Args[i+1:]
-				i = len(os.Args)
-			}
-
-			// we now want to call out to help on a dummy command object, but preserving
-			// Cmd as our current position down the menu structure
-			commandData.Cmd = commandPtr
-			cmdHelp.Parent = commandPtr
-			commandPtr = cmdHelp
+			// take any remaining fields as parameters, preserving Cmd as our current
+			// position down the menu structure
+			commandData.Params = args[i+1:]
+			commandData.help = true
+			result.help = true
+			break
 		} else if commandPtr.Handler == nil {
 			// we're in a parent menu, so this cant be a parameter -- but the next argument
 			// is not a valid subcommand.
-			return helpError(commandData, &ErrCommandInvalid{arg})
+			return result, &ErrCommandInvalid{commandPtr, arg}
 		} else {
 			// we've now reached a child menu, and all that remains are parameters and options
-			commandData.Params = append(commandData.Params, os.Args[i])
+			commandData.Params = append(commandData.Params, args[i])
 			paramParsing = true
 		}
 	}
 
+	return result, nil
+}
+
+// resolveChecked resolves args as resolve() does, then with ctx set on the Data runs
+// the pre-validation callbacks and checks required options and option groups, as
+// is done before the validation callbacks.  A parent Command without a Handler is
+// only accepted at the root, or the level selected by the program name, where help
+// is displayed instead.
+func (c *Command) resolveChecked(ctx context.Context, args []string) (*parseResult, error) {
+	result, err := c.resolve(args)
+	commandData := result.data
+	commandData.Ctx = ctx
+	commandPtr := commandData.Cmd
+
+	if err != nil {
+		return result, err
+	}
+
 	// no subcommand specified
-	if commandPtr.Handler == nil {
-		// dont error if we're at the root level
-		if commandPtr == c {
-			helpUsage(commandData)
-			return nil
+	if !result.help && commandPtr.Handler == nil {
+		if commandPtr == c || commandPtr == commandData.invoked {
+			commandData.help = true
+			return result, nil
 		}
 
-		return helpError(commandData, &ErrCommandMissing{})
+		return result, &ErrCommandMissing{commandPtr}
 	}
 
 	if e := commandPtr.runCallbacksPre(commandData); e != nil {
-		return helpError(commandData, &ErrCallbackPre{e.Error()})
+		return result, &ErrCallbackPre{commandPtr, e}
 	}
 
-	if commandPtr != cmdHelp {
-		if e := commandPtr.hasRequiredOptions(commandData); e != nil {
-			return helpError(commandData, &ErrOptionMissing{e.Error()})
-		}
+	if result.help {
+		return result, nil
+	}
 
-		if e := commandPtr.runCallbacks(commandData); e != nil {
-			return helpError(commandData, &ErrCallback{e.Error()})
-		}
+	if option := commandPtr.hasRequiredOptions(commandData); option != nil {
+		return result, &ErrOptionMissing{commandPtr, option}
 	}
 
-	if e := commandPtr.Handler(commandData); e != nil {
-		return &ErrCommandError{e.Error()}
+	return result, commandPtr.hasOptionGroups(commandData)
+}
+
+// warnDeprecated writes warnings for any deprecated Command or Option objects
+// given on the command line.
+func (r *parseResult) warnDeprecated(out io.Writer) {
+	for _, cmd := range r.path {
+		warnDeprecatedCommand(out, cmd)
 	}
 
-	return nil
+	for _, option := range r.options {
+		warnDeprecatedOption(out, r.data.Cmd, option)
+	}
 }
@@ -0,0 +1,255 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testContextKey struct{}
+
+// ParseContext testing, validate the handler is called with our context and
+// the parsed options and params
+func TestParseContext(t *testing.T) {
+	assert := assert.New(t)
+
+	var called *Data
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.newCommandChild(func(data *Data) error {
+		called = data
+		return nil
+	}).newOption()
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, optionDesc)
+	err := cmdRoot.ParseContext(ctx, []string{cmdRootName, cmdChildName, "-" + optionName, "param"})
+
+	assert.Nil(err)
+	if assert.NotNil(called) {
+		assert.Equal(optionDesc, called.Ctx.Value(testContextKey{}))
+		assert.Contains(called.Options, optionName)
+		assert.Equal([]string{"param"}, called.Params)
+	}
+}
+
+// ParseContext testing, validate a cancelled context returns ErrCancelled
+// without calling the handler
+func TestParseContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	var called bool
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.newCommandChild(func(data *Data) error {
+		called = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := cmdRoot.ParseContext(ctx, []string{cmdRootName, cmdChildName})
+	assert.IsType(&ErrCancelled{}, err)
+	assert.False(called)
+}
+
+// ParseContext testing, validate SignalCancel cancels the handler context
+// on SIGINT and returns ErrCancelled
+func TestParseContextSignal(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.SetSignalCancel(true)
+	cmdRoot.newCommandChild(func(data *Data) error {
+		proc, _ := os.FindProcess(os.Getpid())
+		if err := proc.Signal(os.Interrupt); err != nil {
+			t.Skipf("unable to signal self: %v", err)
+		}
+		<-data.Ctx.Done()
+		return data.Ctx.Err()
+	})
+
+	err := cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName})
+	if assert.IsType(&ErrCancelled{}, err) {
+		assert.Contains(err.Error(), "interrupt")
+	}
+}
+
+// ParseContext testing, validate handler and callback errors are wrapped so
+// the original error is reachable
+func TestParseContextWrapped(t *testing.T) {
+	assert := assert.New(t)
+
+	errSentinel := errors.New("sentinel")
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.Stderr = io.Discard
+	cmdChild := cmdRoot.newCommandChild(func(data *Data) error {
+		return fmt.Errorf("handler: %w", errSentinel)
+	})
+
+	err := cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName})
+	assert.ErrorIs(err, errSentinel)
+	var errCommand *ErrCommandError
+	if assert.ErrorAs(err, &errCommand) {
+		assert.Equal(cmdChild, errCommand.Cmd)
+	}
+
+	cmdChild.BindCallback(func(data *Data) error {
+		return errSentinel
+	})
+
+	err = cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName})
+	assert.ErrorIs(err, errSentinel)
+	assert.IsType(&ErrCallback{}, err)
+}
+
+// ParseContext testing, validate a missing required option reports the option
+// once and exports it
+func TestParseContextOptionMissing(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.Stdout = io.Discard
+	cmdRoot.Stderr = io.Discard
+	option := cmdRoot.newCommandChild(testHandlerFunc).newOption().SetRequired()
+
+	err := cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName})
+	var errMissing *ErrOptionMissing
+	if assert.ErrorAs(err, &errMissing) {
+		assert.Equal(option, errMissing.Option)
+		assert.Equal("Required option missing: "+optionName, err.Error())
+	}
+}
+
+// ParseContext testing, validate each OptionGroup type is enforced
+func TestParseContextOptionGroups(t *testing.T) {
+	tests := []struct {
+		name      string
+		grouptype OptionGroupType
+		args      []string
+		err       error
+	}{
+		{"exclusive", OptionGroupExclusive, []string{"-a"}, nil},
+		{"exclusiveconflict", OptionGroupExclusive, []string{"-a", "-b"}, &ErrOptionConflict{}},
+		{"requires", OptionGroupRequires, []string{"-a", "-b"}, nil},
+		{"requiresnone", OptionGroupRequires, []string{"-b"}, nil},
+		{"requiresmissing", OptionGroupRequires, []string{"-a"}, &ErrOptionRequires{}},
+		{"atleastone", OptionGroupAtLeastOne, []string{"-a", "-b"}, nil},
+		{"atleastonemissing", OptionGroupAtLeastOne, []string{}, &ErrOptionGroupMissing{}},
+		{"exactlyone", OptionGroupExactlyOne, []string{"-b"}, nil},
+		{"exactlyonemissing", OptionGroupExactlyOne, []string{}, &ErrOptionGroupMissing{}},
+		{"exactlyoneconflict", OptionGroupExactlyOne, []string{"-a", "-b"}, &ErrOptionConflict{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmdRoot := newCommandRoot(nil)
+			cmdRoot.Stdout = io.Discard
+			cmdRoot.Stderr = io.Discard
+			optionA := cmdRoot.NewOption("a", "option a", false)
+			optionB := cmdRoot.NewOption("b", "option b", false)
+			cmdRoot.newCommandChild(testHandlerFunc).NewOptionGroup(test.grouptype, optionA, optionB)
+
+			args := append([]string{cmdRootName, cmdChildName}, test.args...)
+			err := cmdRoot.ParseContext(context.Background(), args)
+			if test.err == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.IsType(t, test.err, err)
+			}
+		})
+	}
+}
+
+// ParseContext testing, validate hidden commands and options still parse but
+// are omitted from help, and deprecated ones warn
+func TestParseContextHiddenDeprecated(t *testing.T) {
+	assert := assert.New(t)
+
+	var stdout, stderr bytes.Buffer
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.Stdout = &stdout
+	cmdRoot.Stderr = &stderr
+	cmdRoot.newCommandChild(testHandlerFunc).SetHidden().newOption().SetHidden()
+	cmdRoot.NewCommand("old", "old description", testHandlerFunc).SetDeprecated("renamed", "new")
+
+	assert.Nil(cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "-" + optionName}))
+	assert.Empty(stderr.String())
+
+	assert.Nil(cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "help"}))
+	assert.NotContains(stdout.String(), cmdChildName)
+	assert.Contains(stdout.String(), "Deprecated, use new: old description")
+
+	assert.Nil(cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "old"}))
+	assert.Equal("Warning: command 'root old' is deprecated: renamed, use 'new' instead\n", stderr.String())
+}
+
+// Resolve testing, validate the selected command is found without running it
+func TestResolve(t *testing.T) {
+	var called bool
+	cmdRoot := newCommandRoot(nil)
+	cmdChild := cmdRoot.newCommandChild(func(data *Data) error {
+		called = true
+		return nil
+	})
+	option := cmdChild.NewOption("param", "param description", true).SetRequired()
+
+	data, err := cmdRoot.Resolve([]string{cmdRootName, cmdChildName, "--param", "value"})
+	assert.Nil(t, err)
+	assert.Equal(t, cmdChild, data.Cmd)
+	assert.Equal(t, map[string]string{"param": "value"}, data.Options)
+	assert.False(t, data.IsHelp())
+	assert.False(t, called)
+
+	data, err = cmdRoot.Resolve([]string{cmdRootName, cmdChildName})
+	assert.Equal(t, &ErrOptionMissing{cmdChild, option}, err)
+	assert.Equal(t, cmdChild, data.Cmd)
+
+	data, err = cmdRoot.Resolve([]string{cmdRootName})
+	assert.Nil(t, err)
+	assert.True(t, data.IsHelp())
+}
+
+// Resolve testing, validate pre-validation callbacks may supply required options
+func TestResolveCallbackPre(t *testing.T) {
+	var called bool
+	cmdRoot := newCommandRoot(nil)
+	cmdChild := cmdRoot.newCommandChild(func(data *Data) error {
+		called = true
+		return nil
+	})
+	cmdRoot.NewOption("token", "token description", true).SetRequired()
+	cmdRoot.BindCallbackPre(func(data *Data) error {
+		if _, ok := data.Options["token"]; !ok {
+			data.Options["token"] = "from environment"
+		}
+		return nil
+	})
+
+	args := []string{cmdRootName, cmdChildName}
+	data, err := cmdRoot.Resolve(args)
+	assert.Nil(t, err)
+	assert.Equal(t, cmdChild, data.Cmd)
+	assert.Equal(t, map[string]string{"token": "from environment"}, data.Options)
+	assert.False(t, called)
+
+	assert.Nil(t, cmdRoot.ParseContext(context.Background(), args))
+	assert.True(t, called)
+}
+
+// Parse testing, validate options are keyed by their defined name whatever the case
+func TestParseOptionCase(t *testing.T) {
+	cmdRoot := newCommandRoot(testHandlerFunc)
+	option := cmdRoot.NewOption("param", "param description", true).SetRequired()
+
+	data, err := cmdRoot.Resolve([]string{cmdRootName, "--PARAM", "value"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{option.Name: "value"}, data.Options)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Default exit codes returned by Run() and ExitCode() for each category of error.
+const (
+	// ExitSuccess The Handler ran and returned nil, or help was displayed.
+	ExitSuccess = 0
+	// ExitFailure The Handler returned an error, ErrCommandError.
+	ExitFailure = 1
+	// ExitUsage The command line was invalid: ErrCommandInvalid, ErrCommandMissing,
+	// ErrOptionUnknown, ErrOptionMissing, ErrOptionMissingParam, ErrOptionConflict,
+	// ErrOptionGroupMissing, ErrOptionRequires or ErrHelpKeyword.
+	ExitUsage = 2
+	// ExitValidation A validation callback rejected the command line: ErrCallback
+	// or ErrCallbackPre.
+	ExitValidation = 3
+	// ExitCancelled The context was cancelled, ErrCancelled.
+	ExitCancelled = 130
+)
+
+// Run parses args exactly as ParseContext does using context.Background(), then
+// returns an exit code suitable for passing to os.Exit(), e.g.
+//   func main() {
+//     os.Exit(cliRoot.Run(os.Args))
+//   }
+//
+// Errors from the parser are already reported alongside help information, so Run
+// additionally reports only errors returned from the Handler, cancellation, an
+// option missing its parameter, or an invalid tree when Strict is set.
+func (c *Command) Run(args []string) int {
+	return c.RunContext(context.Background(), args)
+}
+
+// RunContext is the same as Run, but parses args under the supplied context.
+func (c *Command) RunContext(ctx context.Context, args []string) int {
+	return c.ReportError(c.ParseContext(ctx, args))
+}
+
+// ReportError reports an error returned by ParseContext() exactly as Run does, then
+// returns its exit code.  This is useful when calling ParseContext() directly, but
+// wanting the same behaviour as Run.
+func (c *Command) ReportError(err error) int {
+	switch err.(type) {
+	case *ErrCommandError, *ErrCancelled, *ErrOptionMissingParam, *ErrTreeInvalid:
+		var ec *ErrExitCode
+		if !errors.As(err, &ec) || ec.Err != nil {
+			fmt.Fprintf(c.GetRoot().getStderr(), "%s\n", err)
+		}
+	}
+
+	return ExitCode(err)
+}
+
+// ExitCode maps an error returned by Parse() or ParseContext() to its default exit
+// code.  If an ErrExitCode is found within the error chain, its own exit code is
+// used, and any other error not returned from the parser maps to ExitFailure.
+func ExitCode(err error) int {
+	var ec *ErrExitCode
+	if errors.As(err, &ec) {
+		return ec.Code
+	}
+
+	switch err.(type) {
+	case nil:
+		return ExitSuccess
+	case *ErrCommandError:
+		return ExitFailure
+	case *ErrCommandInvalid, *ErrCommandMissing, *ErrOptionUnknown,
+		*ErrOptionMissing, *ErrOptionMissingParam, *ErrOptionConflict,
+		*ErrOptionGroupMissing, *ErrOptionRequires, *ErrHelpKeyword:
+		return ExitUsage
+	case *ErrCallback, *ErrCallbackPre:
+		return ExitValidation
+	case *ErrCancelled:
+		return ExitCancelled
+	}
+
+	return ExitFailure
+}
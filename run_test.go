@@ -0,0 +1,88 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Run testing, validate the exit code for each category of error and that
+// handler errors are reported to Stderr
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		handler Handler
+		code    int
+		stderr  string
+	}{
+		{"success", []string{cmdChildName}, testHandlerFunc, ExitSuccess, ""},
+		{"failure", []string{cmdChildName}, func(data *Data) error {
+			return errors.New("handler failed")
+		}, ExitFailure, "Error: handler failed\n"},
+		{"exitcode", []string{cmdChildName}, func(data *Data) error {
+			return NewErrExitCode(42, errors.New("custom"))
+		}, 42, "Error: custom\n"},
+		{"exitcodesilent", []string{cmdChildName}, func(data *Data) error {
+			return NewErrExitCode(5, nil)
+		}, 5, ""},
+		{"invalid", []string{"invalid"}, testHandlerFunc, ExitUsage, "Invalid subcommand: invalid"},
+		{"unknown", []string{cmdChildName, "-unknown"}, testHandlerFunc, ExitUsage, "Unknown option: -unknown"},
+		{"helpkeyword", []string{"help", "-k"}, testHandlerFunc, ExitUsage, "Error: help -k requires a keyword"},
+		{"missingparam", []string{cmdChildName, "--param"}, testHandlerFunc, ExitUsage, "Missing parameter to option: --param"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var stdout, stderr bytes.Buffer
+			cmdRoot := newCommandRoot(nil)
+			cmdRoot.Stdout = &stdout
+			cmdRoot.Stderr = &stderr
+			cmdRoot.newCommandChild(test.handler)
+
+			code := cmdRoot.Run(append([]string{cmdRootName}, test.args...))
+			assert.Equal(test.code, code)
+			if test.stderr == "" {
+				assert.Empty(stderr.String())
+			} else {
+				assert.Contains(stderr.String(), test.stderr)
+			}
+		})
+	}
+}
+
+// Run testing, validate a failing callback maps to ExitValidation
+func TestRunCallback(t *testing.T) {
+	var stderr bytes.Buffer
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.Stderr = &stderr
+	cmdRoot.newCommandChild(testHandlerFunc).BindCallback(func(data *Data) error {
+		return errors.New("callback failed")
+	})
+
+	assert.Equal(t, ExitValidation, cmdRoot.Run([]string{cmdRootName, cmdChildName}))
+}
+
+// ReportError testing, validate errors are reported to the writer of the root
+// Command without help, when called on a child
+func TestReportError(t *testing.T) {
+	var stderr bytes.Buffer
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.Stderr = &stderr
+	cmdChild := cmdRoot.newCommandChild(testHandlerFunc)
+
+	err := cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName, "--param"})
+	assert.IsType(t, &ErrOptionMissingParam{}, err)
+	assert.Empty(t, stderr.String())
+
+	assert.Equal(t, ExitUsage, cmdChild.ReportError(err))
+	assert.Equal(t, "Missing parameter to option: --param\n", stderr.String())
+}
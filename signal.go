@@ -0,0 +1,67 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SetSignalCancel sets whether the parsing context is cancelled when the program
+// receives SIGINT or SIGTERM, so the Handler can stop cleanly.  This should only be
+// called on the root Command.
+func (c *Command) SetSignalCancel(cancel bool) *Command {
+	c.SignalCancel = cancel
+	return c
+}
+
+// errSignal is the cancellation cause recorded when a signal cancels the context.
+type errSignal struct {
+	sig os.Signal
+}
+
+func (e *errSignal) Error() string {
+	return fmt.Sprintf("received signal %s", e.sig)
+}
+
+// signalContext derives a context from ctx which is cancelled once the program
+// receives SIGINT or SIGTERM.  The returned stop function releases the signal
+// handler and must be called once parsing is complete.
+func signalContext(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	sigc := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-sigc:
+			cancel(&errSignal{sig})
+		case <-done:
+		}
+	}()
+
+	stop := func() {
+		signal.Stop(sigc)
+		close(done)
+		cancel(nil)
+	}
+
+	return ctx, stop
+}
+
+// newErrCancelled builds an ErrCancelled from a cancelled context, recording the
+// signal received where the cancellation was signal driven.
+func newErrCancelled(ctx context.Context, cmd *Command) error {
+	err := &ErrCancelled{Cmd: cmd, Err: context.Cause(ctx)}
+	if sigerr, ok := err.Err.(*errSignal); ok {
+		err.Signal = sigerr.sig
+	}
+
+	return err
+}
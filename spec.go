@@ -0,0 +1,150 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// A Spec describes the interface of a command tree, as seen by the user: the
+// commands, their aliases and options and what is required.  Specs are exported as
+// JSON via WriteSpec(), so the interface of one release can be compared against the
+// next via DiffSpec().
+type Spec struct {
+	// Name Name of the Command
+	Name string `json:"name"`
+	// Desc Description of the Command
+	Desc string `json:"desc,omitempty"`
+	// Aliases Alternative names for the Command
+	Aliases []string `json:"aliases,omitempty"`
+	// Leaf Whether the Command has a Handler
+	Leaf bool `json:"leaf,omitempty"`
+	// Hidden Whether the Command is hidden from help
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated Deprecation details, or nil
+	Deprecated *Deprecation `json:"deprecated,omitempty"`
+	// Options Options bound to the Command
+	Options []*SpecOption `json:"options,omitempty"`
+	// RequiredOptions Options required by the Command and its children via
+	// SetOptionRequired(), as given on the command line
+	RequiredOptions []string `json:"required_options,omitempty"`
+	// OptionGroups Option groups bound to the Command
+	OptionGroups []*SpecOptionGroup `json:"option_groups,omitempty"`
+	// Children Child commands
+	Children []*Spec `json:"children,omitempty"`
+}
+
+// A SpecOption describes an Option within a Spec.
+type SpecOption struct {
+	// Name Name of the Option
+	Name string `json:"name"`
+	// Desc Description of the Option
+	Desc string `json:"desc,omitempty"`
+	// Param Whether the Option takes a parameter
+	Param bool `json:"param,omitempty"`
+	// Required Whether the Option is always required
+	Required bool `json:"required,omitempty"`
+	// Hidden Whether the Option is hidden from help
+	Hidden bool `json:"hidden,omitempty"`
+	// Deprecated Deprecation details, or nil
+	Deprecated *Deprecation `json:"deprecated,omitempty"`
+}
+
+// A SpecOptionGroup describes an OptionGroup within a Spec.
+type SpecOptionGroup struct {
+	// Type Relationship between the Options: "exclusive", "requires", "atleastone"
+	// or "exactlyone"
+	Type string `json:"type"`
+	// Options Options within the group, as given on the command line.  For
+	// "requires" the first Option is the one requiring the others.
+	Options []string `json:"options"`
+}
+
+// specGroupTypes names each OptionGroupType within a Spec.
+var specGroupTypes = map[OptionGroupType]string{
+	OptionGroupExclusive:  "exclusive",
+	OptionGroupRequires:   "requires",
+	OptionGroupAtLeastOne: "atleastone",
+	OptionGroupExactlyOne: "exactlyone",
+}
+
+// String describes the relationship, in the form used within help output.
+func (g *SpecOptionGroup) String() string {
+	options := strings.Join(g.Options, ", ")
+
+	switch g.Type {
+	case "exclusive":
+		return message(nil, MsgRuleExclusive, options)
+	case "requires":
+		if len(g.Options) > 1 {
+			return message(nil, MsgRuleRequires, g.Options[0], strings.Join(g.Options[1:], ", "))
+		}
+	case "atleastone":
+		return message(nil, MsgRuleAtLeastOne, options)
+	case "exactlyone":
+		return message(nil, MsgRuleExactlyOne, options)
+	}
+
+	return options
+}
+
+// GetSpec returns the Spec of this Command and all of its children.
+func (c *Command) GetSpec() *Spec {
+	spec := &Spec{
+		Name:       c.Name,
+		Desc:       c.Desc,
+		Aliases:    c.Aliases,
+		Leaf:       c.Handler != nil,
+		Hidden:     c.Hidden,
+		Deprecated: c.Deprecated,
+	}
+
+	for _, option := range c.Options {
+		spec.Options = append(spec.Options, &SpecOption{
+			Name:       option.Name,
+			Desc:       option.Desc,
+			Param:      option.Param,
+			Required:   option.Required,
+			Hidden:     option.Hidden,
+			Deprecated: option.Deprecated,
+		})
+	}
+
+	for _, option := range c.RequiredOptions {
+		spec.RequiredOptions = append(spec.RequiredOptions, optionFlag(option))
+	}
+
+	for _, group := range c.OptionGroups {
+		specgroup := &SpecOptionGroup{Type: specGroupTypes[group.Type]}
+		for _, option := range group.Options {
+			specgroup.Options = append(specgroup.Options, optionFlag(option))
+		}
+		spec.OptionGroups = append(spec.OptionGroups, specgroup)
+	}
+
+	for _, child := range c.Children {
+		spec.Children = append(spec.Children, child.GetSpec())
+	}
+
+	return spec
+}
+
+// WriteSpec writes the Spec of this Command and all of its children to w as JSON.
+func (c *Command) WriteSpec(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.GetSpec())
+}
+
+// ReadSpec reads a Spec written by WriteSpec() from r.
+func ReadSpec(r io.Reader) (*Spec, error) {
+	var spec Spec
+	if err := json.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSpecTree creates a small tree for comparing releases
+func newSpecTree() *Command {
+	cmdRoot := newCommandRoot(nil)
+	cmdRoot.NewOption("verbose", "verbose output", false)
+	cmdApi := cmdRoot.NewCommand("api", "api description", nil)
+	cmdApi.NewOption("host", "api host", true)
+	cmdApi.NewCommand("get", "get description", testHandlerFunc).SetAliases("fetch")
+	cmdApi.NewCommand("delete", "delete description", testHandlerFunc).NewOption("force", "force delete", false)
+
+	return cmdRoot
+}
+
+// WriteSpec testing, validate specs read back the same
+func TestWriteSpec(t *testing.T) {
+	var out bytes.Buffer
+	cmdRoot := newSpecTree()
+	cmdRoot.GetCommand("api").SetDeprecated("", "v2")
+
+	assert.Nil(t, cmdRoot.WriteSpec(&out))
+	spec, err := ReadSpec(&out)
+	assert.Nil(t, err)
+	assert.Equal(t, cmdRoot.GetSpec(), spec)
+	assert.Equal(t, "v2", spec.Children[0].Deprecated.Replacement)
+	assert.Equal(t, []string{"fetch"}, spec.Children[0].Children[0].Aliases)
+
+	_, err = ReadSpec(bytes.NewBufferString("{"))
+	assert.NotNil(t, err)
+}
+
+// DiffSpec testing, validate an unchanged tree has no changes
+func TestDiffSpecUnchanged(t *testing.T) {
+	assert.Nil(t, DiffSpec(newSpecTree().GetSpec(), newSpecTree().GetSpec()))
+}
+
+// DiffSpec testing, validate breaking changes, additions and deprecations
+func TestDiffSpec(t *testing.T) {
+	from := newSpecTree()
+
+	to := newSpecTree()
+	cmdApi := to.GetCommand("api")
+	cmdApi.UnbindOption(cmdApi.GetOption("host", true))
+	to.NewOption("host", "api host", false)
+	to.GetOption("verbose", false).SetDeprecated("", "")
+	cmdApi.GetCommand("get").SetName("show")
+	cmdApi.GetCommand("show").SetAliases("get")
+	cmdDelete := cmdApi.GetCommand("delete")
+	cmdDelete.SetOptionRequired(cmdDelete.GetOption("force", false))
+	cmdApi.NewCommand("list", "list description", testHandlerFunc)
+
+	var changes []string
+	for _, change := range DiffSpec(from.GetSpec(), to.GetSpec()) {
+		changes = append(changes, change.String())
+	}
+
+	assert.Equal(t, []string{
+		"deprecation: root: option -verbose is deprecated",
+		"addition: root: option -host added",
+		"breaking: root api: option --host changed to -host",
+		"addition: root api show: accepts the new name 'show'",
+		"breaking: root api delete: option -force now required",
+		"addition: root api list: command added",
+	}, changes)
+
+	changes = nil
+	for _, change := range DiffSpec(to.GetSpec(), from.GetSpec()) {
+		changes = append(changes, change.String())
+	}
+	assert.Contains(t, changes, "breaking: root api list: command removed")
+	assert.Contains(t, changes, "breaking: root: option -host removed")
+}
+
+// DiffSpec testing, validate new or tightened option groups are breaking, and those
+// removed, loosened or only over new options are not
+func TestDiffSpecOptionGroups(t *testing.T) {
+	diff := func(from, to *Command) []string {
+		var changes []string
+		for _, change := range DiffSpec(from.GetSpec(), to.GetSpec()) {
+			changes = append(changes, change.String())
+		}
+		return changes
+	}
+
+	from := newSpecTree()
+	fromDelete := from.FindPath("api", "delete")
+	fromDelete.NewOption("all", "delete all", false)
+	fromDelete.NewOptionGroup(OptionGroupAtLeastOne, fromDelete.GetOption("force", false), fromDelete.GetOption("all", false))
+
+	to := from.Clone()
+	toApi := to.GetCommand("api")
+	toDelete := toApi.GetCommand("delete")
+	toApi.NewOptionGroup(OptionGroupExclusive, toApi.GetOption("host", true), to.GetOption("verbose", false))
+	toDelete.OptionGroups[0].Type = OptionGroupExactlyOne
+	toDelete.NewOptionGroup(OptionGroupExclusive, toDelete.GetOption("force", false), toDelete.NewOption("dry", "dry run", false))
+
+	assert.Equal(t, []string{
+		"breaking: root api: option rule added: mutually exclusive: --host, -verbose",
+		"addition: root api delete: option -dry added",
+		"breaking: root api delete: option rule added: exactly one of: -force, -all",
+	}, diff(from, to))
+
+	assert.Equal(t, []string{
+		"addition: root api: option rule removed: mutually exclusive: --host, -verbose",
+		"breaking: root api delete: option -dry removed",
+		"addition: root api delete: option rule removed: exactly one of: -force, -all",
+	}, diff(to, from))
+
+	// moving a group to a parent constrains its siblings, so is breaking
+	moved := from.Clone()
+	movedDelete := moved.FindPath("api", "delete")
+	group := movedDelete.OptionGroups[0]
+	movedDelete.OptionGroups = nil
+	moved.NewOptionGroup(group.Type, group.Options...)
+	assert.Equal(t, []string{
+		"breaking: root: option rule added: at least one of: -force, -all",
+	}, diff(from, moved))
+	assert.Equal(t, []string{
+		"addition: root: option rule removed: at least one of: -force, -all",
+	}, diff(moved, from))
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package clicommand
+
+import (
+	"io"
+)
+
+// terminalWidth always returns false, as terminal detection is not supported on
+// this platform.
+func terminalWidth(out io.Writer) (int, bool) {
+	return 0, false
+}
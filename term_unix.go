@@ -0,0 +1,39 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package clicommand
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the structure filled in by the TIOCGWINSZ ioctl.
+type winsize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+// terminalWidth returns the width of the terminal out is attached to, or false if
+// out is not a terminal.
+func terminalWidth(out io.Writer) (int, bool) {
+	file, ok := out.(*os.File)
+	if !ok {
+		return 0, false
+	}
+
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(),
+		uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, false
+	}
+
+	return int(ws.Col), true
+}
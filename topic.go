@@ -0,0 +1,138 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Topic represents a help page which is not a command, such as a description of
+// environment variables or a configuration file format.  Topics are registered on
+// the root Command and displayed via "help <topic>".
+type Topic struct {
+	// Name Name of the topic, as given after "help"
+	Name string
+	// Desc Short description of the topic
+	Desc string
+	// Text Full text of the topic.  Blank lines separate paragraphs.
+	Text string
+}
+
+// NewTopic creates a new Topic and registers it on the root of the tree the Command
+// is within, so it can be displayed via "help <name>".
+func (c *Command) NewTopic(name string, desc string, text string) *Topic {
+	topic := &Topic{
+		Name: name,
+		Desc: desc,
+		Text: text,
+	}
+
+	root := c.GetRoot()
+	root.Topics = append(root.Topics, topic)
+
+	return topic
+}
+
+// GetTopic finds a Topic registered on the tree with the given name, or nil if not
+// found.  name matches are case-insensitive.
+func (c *Command) GetTopic(name string) *Topic {
+	for _, topic := range c.GetRoot().Topics {
+		if strings.EqualFold(topic.Name, name) {
+			return topic
+		}
+	}
+
+	return nil
+}
+
+// helpTopic writes the text of a Topic.
+func helpTopic(data *Data, topic *Topic) error {
+	root := data.Cmd.GetRoot()
+	out := root.getStdout()
+	palette := root.getPalette(out, data)
+	width := root.getHelpWidth(out)
+
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "%s - %s\n", palette.heading(topic.Name), wrapText(topic.Desc, len(topic.Name)+3, width))
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "%s\n", wrapText(topic.Text, 0, width))
+	fmt.Fprintf(out, "\n")
+
+	return nil
+}
+
+// helpSearch writes every visible Command and Topic within the tree whose name,
+// aliases or descriptions contain keyword, ignoring case.
+func helpSearch(data *Data, keyword string) error {
+	if keyword == "" {
+		return &ErrHelpKeyword{data.Cmd}
+	}
+
+	root := data.Cmd.GetRoot()
+	out := root.getStdout()
+	palette := root.getPalette(out, data)
+	width := root.getHelpWidth(out)
+
+	var commands []*Command
+	var colwidth int
+	helpSearchRecurse(root, strings.ToLower(keyword), &commands)
+	for _, cmd := range commands {
+		colwidth = helpMax(colwidth, "  "+cmd.GetNameChain())
+	}
+
+	fmt.Fprintf(out, "\n")
+	if len(commands) == 0 {
+		fmt.Fprintf(out, "%s\n", message(data.Cmd, MsgSearchNone, keyword))
+	} else {
+		fmt.Fprintf(out, "%s\n", palette.heading(message(data.Cmd, MsgSearchCommands, keyword)))
+		for _, cmd := range commands {
+			fmt.Fprintf(out, "%s\n", wrapRow("  "+cmd.GetNameChain(), colwidth, cmd.GetDesc(), width))
+		}
+	}
+	fmt.Fprintf(out, "\n")
+
+	var topics []*Topic
+	colwidth = 0
+	for _, topic := range root.Topics {
+		if helpSearchMatch(strings.ToLower(keyword), topic.Name, topic.Desc, topic.Text) {
+			topics = append(topics, topic)
+			colwidth = helpMax(colwidth, "  "+topic.Name)
+		}
+	}
+
+	if len(topics) > 0 {
+		fmt.Fprintf(out, "%s\n", palette.heading(message(data.Cmd, MsgSearchTopics, keyword)))
+		for _, topic := range topics {
+			fmt.Fprintf(out, "%s\n", wrapRow("  "+topic.Name, colwidth, topic.Desc, width))
+		}
+		fmt.Fprintf(out, "\n")
+	}
+
+	return nil
+}
+
+// helpSearchRecurse appends cmd and its visible descendants matching keyword to
+// matches, in the order configured for the tree.
+func helpSearchRecurse(cmd *Command, keyword string, matches *[]*Command) {
+	if cmd.Parent != nil && helpSearchMatch(keyword, append([]string{cmd.Name, cmd.Desc, cmd.GetDesc(), cmd.LongDesc}, cmd.Aliases...)...) {
+		*matches = append(*matches, cmd)
+	}
+
+	for _, child := range helpChildren(cmd) {
+		helpSearchRecurse(child, keyword, matches)
+	}
+}
+
+// helpSearchMatch returns whether any of fields contain keyword, which must be
+// lower case, ignoring case.
+func helpSearchMatch(keyword string, fields ...string) bool {
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), keyword) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// treeLine is a single Command within the output of WriteTree.
+type treeLine struct {
+	cmd   *Command
+	label string
+	kind  string
+}
+
+// WriteTree writes an overview of this Command and all of its children to w, one
+// Command per line and indented by depth.  Each line shows the name, whether the
+// Command is a leaf with a Handler or a parent, its description and the options
+// bound directly to it, e.g.
+//   helloworld  parent  Sample hello world program [-u, -lower]
+//     hello     leaf    Says hello world
+//     say       leaf    Says something [--say]
+//
+// Hidden and deprecated commands and options are included, but marked as such.
+func (c *Command) WriteTree(w io.Writer) error {
+	return c.writeTree(w, true)
+}
+
+// SetHelpTree sets whether "help -tree" is available, showing the overview written
+// by WriteTree() without hidden commands and options.  This should only be called
+// on the root Command.
+func (c *Command) SetHelpTree(enabled bool) *Command {
+	c.HelpTree = enabled
+	return c
+}
+
+// writeTree writes the tree overview, optionally including hidden commands and
+// options.
+func (c *Command) writeTree(w io.Writer, hidden bool) error {
+	var lines []*treeLine
+	treeRecurse(c, 0, hidden, &lines)
+
+	var labelwidth, kindwidth int
+	for _, line := range lines {
+		labelwidth = helpMax(labelwidth, line.label)
+		kindwidth = helpMax(kindwidth, line.kind)
+	}
+
+	for _, line := range lines {
+		text := fmt.Sprintf("%-*s  %-*s  %s", labelwidth, line.label, kindwidth, line.kind, line.cmd.GetDesc())
+		if options := treeOptions(line.cmd, hidden); options != "" {
+			text += " [" + options + "]"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\n", strings.TrimRight(text, " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// treeRecurse appends the line for cmd and each of its children to lines.
+func treeRecurse(cmd *Command, depth int, hidden bool, lines *[]*treeLine) {
+	if cmd.Hidden && !hidden && depth > 0 {
+		return
+	}
+
+	kind := message(cmd, MsgTreeLeaf)
+	if cmd.Handler == nil {
+		kind = message(cmd, MsgTreeParent)
+	}
+
+	if cmd.Hidden {
+		kind += "," + message(cmd, MsgTreeHidden)
+	}
+
+	if cmd.Deprecated != nil {
+		kind += "," + message(cmd, MsgTreeDeprecated)
+	}
+
+	*lines = append(*lines, &treeLine{
+		cmd:   cmd,
+		label: strings.Repeat("  ", depth) + cmd.Name,
+		kind:  kind,
+	})
+
+	for _, child := range cmd.GetChildren() {
+		treeRecurse(child, depth+1, hidden, lines)
+	}
+}
+
+// treeOptions returns a comma separated list of the options bound directly to cmd.
+func treeOptions(cmd *Command, hidden bool) string {
+	var options []string
+	for _, option := range cmd.Options {
+		if option.Hidden && !hidden {
+			continue
+		}
+
+		name := optionFlag(option)
+		if option.Hidden {
+			name += " (" + message(cmd, MsgTreeHidden) + ")"
+		}
+		if option.Deprecated != nil {
+			name += " (" + message(cmd, MsgTreeDeprecated) + ")"
+		}
+
+		options = append(options, name)
+	}
+
+	return strings.Join(options, ", ")
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTreeTree creates a small tree with hidden and deprecated entries
+func newTreeTree() (*Command, *bytes.Buffer) {
+	cmdRoot, out := newHelpTree()
+	cmdApi := cmdRoot.NewCommand("api", "api description", nil)
+	cmdApi.NewOption("secret", "secret option", true).SetHidden()
+	cmdApi.NewCommand("get", "get description", testHandlerFunc).SetDeprecated("", "")
+	cmdApi.NewCommand("debug", "debug description", testHandlerFunc).SetHidden()
+
+	return cmdRoot, out
+}
+
+// WriteTree testing, validate all commands are shown with their type and options
+func TestWriteTree(t *testing.T) {
+	var out bytes.Buffer
+	cmdRoot, _ := newTreeTree()
+
+	assert.Nil(t, cmdRoot.WriteTree(&out))
+	assert.Equal(t, `root       parent           root description [-option]
+  child    leaf             child description [--param]
+  api      parent           api description [--secret (hidden)]
+    get    leaf,deprecated  get description
+    debug  leaf,hidden      debug description
+`, out.String())
+}
+
+// helpUsage testing, validate help -tree is opt-in and omits hidden entries
+func TestHelpTree(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdRoot, out := newTreeTree()
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "api", "help", "-tree"})
+	assert.Contains(out.String(), "api - api description")
+
+	out.Reset()
+	cmdRoot.SetHelpTree(true)
+	cmdRoot.ParseContext(context.Background(), []string{cmdRootName, "api", "help", "-tree"})
+	assert.Equal(`api    parent           api description
+  get  leaf,deprecated  get description
+`, out.String())
+}
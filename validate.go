@@ -0,0 +1,163 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Problem is a mistake within a command tree, found by Validate().  Problems
+// generally either fail silently or only show up at runtime, such as one of two
+// children with the same name never being reachable.
+type Problem struct {
+	// Cmd Command the problem was found at
+	Cmd *Command
+	// Option Option the problem concerns, or nil
+	Option *Option
+	// Message Description of the problem
+	Message string
+}
+
+// String describes the problem, prefixed by the Command and Option it concerns.
+func (p Problem) String() string {
+	if p.Option != nil {
+		return fmt.Sprintf("%s: option %s: %s", p.Cmd.GetNameChain(), optionFlag(p.Option), p.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", p.Cmd.GetNameChain(), p.Message)
+}
+
+// Validate checks this Command and all of its children for mistakes, returning every
+// Problem found, or nil if there are none.  The checks are:
+//   - children or aliases with the same name, ignoring case
+//   - a flag and a parameter option sharing a name
+//   - options shadowing an option of the same name bound to a parent
+//   - commands or options with an empty description
+//   - parents without a handler or children
+//   - required options or option groups using options not available to the command
+//
+// Validate is intended to be called from tests, or via MustValidate().  Setting Strict
+// on the root Command additionally validates the tree on every parse.
+func (c *Command) Validate() []Problem {
+	var problems []Problem
+	c.validateRecurse(&problems)
+	return problems
+}
+
+// MustValidate calls Validate(), panicking if any Problem is found.  This is useful
+// within tests, or at startup for trees which are assembled dynamically.
+func (c *Command) MustValidate() {
+	if problems := c.Validate(); len(problems) > 0 {
+		panic("MustValidate() " + (&ErrTreeInvalid{c, problems}).Error())
+	}
+}
+
+// SetStrict sets whether the tree is validated on every parse, with ParseContext()
+// returning an ErrTreeInvalid error if any Problem is found.  This should only be
+// called on the root Command.
+func (c *Command) SetStrict(strict bool) *Command {
+	c.Strict = strict
+	return c
+}
+
+// validateStrict validates the tree if Strict is set on the root Command.
+func (c *Command) validateStrict() error {
+	if !c.GetRoot().Strict {
+		return nil
+	}
+
+	if problems := c.Validate(); len(problems) > 0 {
+		return &ErrTreeInvalid{c, problems}
+	}
+
+	return nil
+}
+
+// validateRecurse appends the problems found at this Command and its children.
+func (c *Command) validateRecurse(problems *[]Problem) {
+	add := func(option *Option, format string, args ...interface{}) {
+		*problems = append(*problems, Problem{c, option, fmt.Sprintf(format, args...)})
+	}
+
+	if c.Desc == "" && c.DescKey == "" {
+		add(nil, "empty description")
+	}
+
+	if c.Handler == nil && len(c.Children) == 0 {
+		add(nil, "no handler and no subcommands")
+	}
+
+	names := make(map[string]*Command)
+	for _, child := range c.Children {
+		for _, name := range append([]string{child.Name}, child.Aliases...) {
+			key := strings.ToLower(name)
+			if other, ok := names[key]; ok {
+				add(nil, "subcommands '%s' and '%s' share the name '%s'", other.Name, child.Name, name)
+			} else {
+				names[key] = child
+			}
+		}
+	}
+
+	for i, option := range c.Options {
+		if option.Desc == "" && option.DescKey == "" {
+			add(option, "empty description")
+		}
+
+		for _, other := range c.Options[:i] {
+			if other != option && strings.EqualFold(other.Name, option.Name) {
+				if other.Param == option.Param {
+					add(option, "duplicate option")
+				} else {
+					add(option, "flag and parameter option share the name '%s'", option.Name)
+				}
+			}
+		}
+
+		for parent := c.Parent; parent != nil; parent = parent.Parent {
+			for _, other := range parent.Options {
+				if other != option && strings.EqualFold(other.Name, option.Name) {
+					if other.Param == option.Param {
+						add(option, "shadows option bound to '%s'", parent.GetNameChain())
+					} else {
+						add(option, "flag and parameter option share the name '%s' with '%s'", option.Name, parent.GetNameChain())
+					}
+				}
+			}
+		}
+	}
+
+	for _, option := range c.RequiredOptions {
+		if !c.optionAvailable(option) {
+			add(option, "required option is not available to the command")
+		}
+	}
+
+	for _, group := range c.OptionGroups {
+		for _, option := range group.Options {
+			if !c.optionAvailable(option) {
+				add(option, "option within group is not available to the command")
+			}
+		}
+	}
+
+	for _, child := range c.Children {
+		child.validateRecurse(problems)
+	}
+}
+
+// optionAvailable returns whether the Option is bound to this Command or one of its
+// parents.
+func (c *Command) optionAvailable(option *Option) bool {
+	for cmd := c; cmd != nil; cmd = cmd.Parent {
+		for _, candidate := range cmd.Options {
+			if candidate == option {
+				return true
+			}
+		}
+	}
+
+	return false
+}
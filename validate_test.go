@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Validate testing, validate a clean tree has no problems
+func TestValidate(t *testing.T) {
+	cmdRoot, _ := newHelpTree()
+	assert.Nil(t, cmdRoot.Validate())
+	assert.NotPanics(t, cmdRoot.MustValidate)
+}
+
+// Validate testing, validate each mistake is reported
+func TestValidateProblems(t *testing.T) {
+	cmdRoot, _ := newHelpTree()
+	cmdRoot.NewCommand("CHILD", "", testHandlerFunc)
+	cmdRoot.NewCommand("empty", "empty description", nil).SetAliases("alias")
+	cmdChild := cmdRoot.GetCommand(cmdChildName)
+	cmdChild.NewOption(optionName, "shadows root", false)
+	cmdChild.NewOption("param", "", false)
+	cmdChild.SetOptionRequired(NewOption("unbound", "unbound description", false))
+
+	var problems []string
+	for _, problem := range cmdRoot.Validate() {
+		problems = append(problems, problem.String())
+	}
+
+	assert.Equal(t, []string{
+		"root: subcommands 'child' and 'CHILD' share the name 'CHILD'",
+		"root: subcommands 'child' and 'empty' share the name 'alias'",
+		"root child: option -option: shadows option bound to 'root'",
+		"root child: option -param: empty description",
+		"root child: option -param: flag and parameter option share the name 'param'",
+		"root child: option -unbound: required option is not available to the command",
+		"root CHILD: empty description",
+		"root empty: no handler and no subcommands",
+	}, problems)
+	assert.Panics(t, cmdRoot.MustValidate)
+}
+
+// Strict testing, validate an invalid tree is refused
+func TestValidateStrict(t *testing.T) {
+	cmdRoot, out := newHelpTree()
+	cmdRoot.SetStrict(true)
+	assert.Nil(t, cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName}))
+
+	cmdRoot.NewCommand("empty", "empty description", nil)
+	out.Reset()
+
+	err := cmdRoot.ParseContext(context.Background(), []string{cmdRootName, cmdChildName})
+	assert.EqualError(t, err, "Invalid command tree: root empty: no handler and no subcommands")
+	assert.Equal(t, "", out.String())
+
+	assert.Equal(t, ExitFailure, cmdRoot.Run([]string{cmdRootName}))
+	assert.Equal(t, "Invalid command tree: root empty: no handler and no subcommands\n", out.String())
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"reflect"
+)
+
+// ErrorVerbosity defines how much information is written alongside an error when
+// parsing fails.
+type ErrorVerbosity int
+
+const (
+	// ErrorsHelp Full help for the command is written, followed by the error.
+	ErrorsHelp ErrorVerbosity = iota
+	// ErrorsUsage The usage line for the command is written, followed by the error.
+	ErrorsUsage
+	// ErrorsOnly Only the error itself is written.
+	ErrorsOnly
+)
+
+// SetErrorVerbosity sets how much information is written alongside parsing errors.
+// If no errors are given, this sets the default for the whole tree.  Otherwise it
+// applies only to errors of the same types as those given, e.g.
+//   cliRoot.SetErrorVerbosity(clicommand.ErrorsUsage)
+//   cliRoot.SetErrorVerbosity(clicommand.ErrorsOnly, &clicommand.ErrCallback{})
+//
+// This should only be called on the root Command.
+func (c *Command) SetErrorVerbosity(verbosity ErrorVerbosity, errv ...error) *Command {
+	if len(errv) == 0 {
+		c.ErrorVerbosity = verbosity
+		return c
+	}
+
+	if c.errorVerbosity == nil {
+		c.errorVerbosity = make(map[reflect.Type]ErrorVerbosity)
+	}
+
+	for _, err := range errv {
+		c.errorVerbosity[reflect.TypeOf(err)] = verbosity
+	}
+
+	return c
+}
+
+// getErrorVerbosity returns the verbosity for the given error, falling back to the
+// default for the tree.  This should only be called on the root Command.
+func (c *Command) getErrorVerbosity(err error) ErrorVerbosity {
+	if verbosity, ok := c.errorVerbosity[reflect.TypeOf(err)]; ok {
+		return verbosity
+	}
+
+	return c.ErrorVerbosity
+}
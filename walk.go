@@ -0,0 +1,93 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"errors"
+	"strings"
+)
+
+// SkipCommand is returned by a WalkFunc to skip the children of the Command it was
+// called for.  It is never returned by Walk().
+var SkipCommand = errors.New("skip this command")
+
+// A WalkFunc is called by Walk() for each Command within the tree, along with its
+// depth below the Command Walk() was called on.  Returning SkipCommand skips the
+// children of cmd, whilst returning any other error stops the walk.
+type WalkFunc func(cmd *Command, depth int) error
+
+// Walk calls fn for this Command and then each of its children, recursively, in the
+// order configured for the tree.  Hidden commands are included.  If fn returns an
+// error other than SkipCommand, the walk stops and the error is returned, e.g.
+//   cliRoot.Walk(func(cmd *clicommand.Command, depth int) error {
+//     if cmd.Hidden {
+//       return clicommand.SkipCommand
+//     }
+//     fmt.Printf("%s%s\n", strings.Repeat("  ", depth), cmd.Name)
+//     return nil
+//   })
+func (c *Command) Walk(fn WalkFunc) error {
+	return c.walk(fn, 0)
+}
+
+// walk calls fn for this Command at depth, then its children.
+func (c *Command) walk(fn WalkFunc, depth int) error {
+	if err := fn(c, depth); err == SkipCommand {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, child := range c.GetChildren() {
+		if err := child.walk(fn, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindPath returns the Command found by following the names from this Command down
+// through its children, matching names and aliases as the parser does, or nil if
+// there is no such Command.  With no names, the Command itself is returned, e.g.
+//   cmd := cliRoot.FindPath("api", "get")
+func (c *Command) FindPath(names ...string) *Command {
+	cmd := c
+	for _, name := range names {
+		if cmd = cmd.GetCommand(name); cmd == nil {
+			return nil
+		}
+	}
+
+	return cmd
+}
+
+// GetOptionsVisible returns every Option which may be given to this Command, being
+// those bound to it or any of its parents, in order from the root down.  Options
+// shadowed by an Option of the same name and type bound closer to this Command are
+// omitted, as the parser never selects them.
+func (c *Command) GetOptionsVisible() []*Option {
+	var options []*Option
+	if c.Parent != nil {
+		for _, option := range c.Parent.GetOptionsVisible() {
+			if c.getOptionLocal(option.Name, option.Param) == nil {
+				options = append(options, option)
+			}
+		}
+	}
+
+	return append(options, c.Options...)
+}
+
+// getOptionLocal returns the Option bound directly to this Command with the given
+// name and type, or nil.
+func (c *Command) getOptionLocal(name string, param bool) *Option {
+	for _, option := range c.Options {
+		if strings.EqualFold(option.Name, name) && option.Param == param {
+			return option
+		}
+	}
+
+	return nil
+}
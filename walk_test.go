@@ -0,0 +1,79 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Walk testing, validate commands are visited in order with their depth
+func TestWalk(t *testing.T) {
+	var visited []string
+	cmdRoot := newSpecTree()
+
+	err := cmdRoot.Walk(func(cmd *Command, depth int) error {
+		visited = append(visited, fmt.Sprintf("%d %s", depth, cmd.GetNameChain()))
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"0 root", "1 root api", "2 root api get", "2 root api delete"}, visited)
+}
+
+// Walk testing, validate subtrees can be skipped and errors stop the walk
+func TestWalkSkip(t *testing.T) {
+	var visited []int
+	cmdRoot := newSpecTree()
+	cmdRoot.NewCommand("status", "status description", testHandlerFunc)
+
+	err := cmdRoot.Walk(func(cmd *Command, depth int) error {
+		visited = append(visited, depth)
+		if cmd.Name == "api" {
+			return SkipCommand
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 1}, visited)
+
+	stop := errors.New("stop")
+	err = cmdRoot.Walk(func(cmd *Command, depth int) error {
+		if depth == 1 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+}
+
+// FindPath testing, validate names and aliases are followed
+func TestFindPath(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdGet := cmdRoot.GetCommand("api").GetCommand("get")
+
+	assert.Equal(t, cmdGet, cmdRoot.FindPath("api", "get"))
+	assert.Equal(t, cmdGet, cmdRoot.FindPath("API", "fetch"))
+	assert.Equal(t, cmdRoot, cmdRoot.FindPath())
+	assert.Nil(t, cmdRoot.FindPath("api", "missing"))
+	assert.Nil(t, cmdRoot.FindPath("api", "get", "deeper"))
+}
+
+// GetOptionsVisible testing, validate inherited options are listed unless shadowed
+func TestGetOptionsVisible(t *testing.T) {
+	cmdRoot := newSpecTree()
+	cmdApi := cmdRoot.GetCommand("api")
+	cmdDelete := cmdApi.GetCommand("delete")
+	optVerbose := cmdRoot.GetOption("verbose", false)
+	optHost := cmdApi.GetOption("host", true)
+	optForce := cmdDelete.GetOption("force", false)
+
+	assert.Equal(t, []*Option{optVerbose}, cmdRoot.GetOptionsVisible())
+	assert.Equal(t, []*Option{optVerbose, optHost, optForce}, cmdDelete.GetOptionsVisible())
+
+	optShadow := cmdDelete.NewOption("HOST", "shadows api host", true)
+	assert.Equal(t, []*Option{optVerbose, optForce, optShadow}, cmdDelete.GetOptionsVisible())
+}
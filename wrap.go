@@ -0,0 +1,80 @@
+// Copyright (C) 2018 Lee H <lee@leeh.uk>
+// Licensed under the BSD 2-Clause License as found in LICENSE.txt
+
+package clicommand
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// wrapMinWidth is the narrowest column text is wrapped into, regardless of how
+// far it is indented.
+const wrapMinWidth = 20
+
+// wrapText wraps text so it fits within width, where the first line starts at
+// column indent and each following line is indented by indent spaces, giving a
+// hanging indent.  Existing newlines within text are preserved.
+func wrapText(text string, indent int, width int) string {
+	avail := width - indent
+	if avail < wrapMinWidth {
+		avail = wrapMinWidth
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line string
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && textWidth(line)+1+textWidth(word) > avail {
+				lines = append(lines, line)
+				line = ""
+			}
+
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"+strings.Repeat(" ", indent))
+}
+
+// wrapRow renders a two column row, padding label to colwidth and wrapping text
+// into the remaining width.  Labels longer than the column have their text start
+// on the following line instead.
+func wrapRow(label string, colwidth int, text string, width int) string {
+	if colwidth > width/2 {
+		colwidth = width / 2
+	}
+
+	labelwidth := textWidth(label)
+	if labelwidth > colwidth {
+		return label + "\n" + strings.Repeat(" ", colwidth+1) + wrapText(text, colwidth+1, width)
+	}
+
+	return label + strings.Repeat(" ", colwidth-labelwidth) + " " + wrapText(text, colwidth+1, width)
+}
+
+// textWidth returns the number of columns text occupies when displayed, which
+// excludes any ANSI escape sequences used for colour.
+func textWidth(text string) int {
+	width := 0
+	for {
+		start := strings.Index(text, "\x1b[")
+		if start < 0 {
+			return width + utf8.RuneCountInString(text)
+		}
+
+		width += utf8.RuneCountInString(text[:start])
+		text = text[start+2:]
+
+		// skip parameters up to and including the final byte of the sequence
+		end := strings.IndexFunc(text, func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+		if end < 0 {
+			return width
+		}
+		text = text[end+1:]
+	}
+}